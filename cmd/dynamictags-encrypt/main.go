@@ -0,0 +1,76 @@
+// Command dynamictags-encrypt encrypts values for configuration files.
+// Usage:
+//
+//	dynamictags-encrypt -generate-key > config.key
+//	dynamictags-encrypt -key-file config.key 'db password'
+//	echo 'db password' | DYNAMICTAGS_KEY_FILE=config.key dynamictags-encrypt
+//	dynamictags-encrypt -key-file config.key -decrypt 'ENC[AES256_GCM,data:...,iv:...]'
+//
+// If value is not specified it is read from standard input (trailing new
+// line is removed). If key file is not specified key is get from
+// 'DYNAMICTAGS_KEY' or 'DYNAMICTAGS_KEY_FILE' environment variables.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/maygli/dynamictags"
+)
+
+func main() {
+	keyFile := flag.String("key-file", "", "path to the file with base64 encoded key")
+	generateKey := flag.Bool("generate-key", false, "generate new key")
+	decrypt := flag.Bool("decrypt", false, "decrypt value")
+	flag.Parse()
+	err := run(*keyFile, *generateKey, *decrypt, flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(keyFile string, generateKey bool, decrypt bool, args []string) error {
+	if generateKey {
+		key, err := dynamictags.GenerateEncryptionKey()
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+		return nil
+	}
+	var key []byte
+	var err error
+	if keyFile != "" {
+		key, err = dynamictags.ReadEncryptionKeyFile(keyFile)
+	} else {
+		key, err = dynamictags.LoadEncryptionKey()
+	}
+	if err != nil {
+		return err
+	}
+	var value string
+	if len(args) > 0 {
+		value = strings.Join(args, " ")
+	} else {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		value = strings.TrimSuffix(strings.TrimSuffix(string(content), "\n"), "\r")
+	}
+	var res string
+	if decrypt {
+		res, err = dynamictags.DecryptValue(value, key)
+	} else {
+		res, err = dynamictags.EncryptValue(value, key)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(res)
+	return nil
+}
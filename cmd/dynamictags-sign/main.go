@@ -0,0 +1,84 @@
+// Command dynamictags-sign signs configuration files with ed25519 key.
+// Usage:
+//
+//	dynamictags-sign -generate-key signing
+//	dynamictags-sign -key-file signing.key config.json
+//	dynamictags-sign -key-file signing.key -embed config.json
+//
+// '-generate-key' writes private key to '<name>.key' and public key to
+// '<name>.pub' files. By default detached signature is written to
+// '<file>.sig' file. With '-embed' signature is embedded into the file as
+// '$signature' key.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/maygli/dynamictags"
+)
+
+func main() {
+	keyFile := flag.String("key-file", "", "path to the file with base64 encoded private key")
+	generateKey := flag.Bool("generate-key", false, "generate new key pair")
+	embed := flag.Bool("embed", false, "embed signature into the file")
+	flag.Parse()
+	err := run(*keyFile, *generateKey, *embed, flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(keyFile string, generateKey bool, embed bool, args []string) error {
+	if len(args) != 1 {
+		return errors.New("one file name is expected")
+	}
+	if generateKey {
+		publicKey, privateKey, err := dynamictags.GenerateSigningKey()
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(args[0]+".key", []byte(privateKey+"\n"), 0600)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(args[0]+".pub", []byte(publicKey+"\n"), 0644)
+	}
+	if keyFile == "" {
+		return errors.New("key file is not specified")
+	}
+	keyContent, err := os.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	key, err := dynamictags.ParsePrivateKey(string(keyContent))
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	if !embed {
+		signature := dynamictags.SignConfig(content, key)
+		return os.WriteFile(args[0]+dynamictags.SIGNATURE_FILE_SUFFIX, []byte(signature+"\n"), 0644)
+	}
+	var tree any
+	err = json.Unmarshal(content, &tree)
+	if err != nil {
+		return err
+	}
+	signed, err := dynamictags.SignConfigTree(tree, key)
+	if err != nil {
+		return err
+	}
+	content, err = json.MarshalIndent(signed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(args[0], append(content, '\n'), 0644)
+}
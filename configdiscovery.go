@@ -0,0 +1,155 @@
+package dynamictags
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Configuration file candidate skipped during discovery.
+type ConfigCandidate struct {
+	// Candidate path (directory if directory is skipped)
+	Path string
+	// Reason why the candidate is skipped
+	Reason string
+}
+
+// Result of configuration file discovery.
+type ConfigDiscovery struct {
+	// Path to the found file. Empty if file is not found
+	Path string
+	// Skipped candidates in search order
+	Skipped []ConfigCandidate
+}
+
+// Returns default configuration directories in search order:
+// '${XDG_CONFIG_HOME}/<appName>', '${HOME}/.config/<appName>', '/etc/<appName>'
+// and working directory.
+// Parameters:
+//   - appName application name
+//
+// Returns:
+//   - list of directories. Directories contain placeholders processed by ProcessString
+func DefaultConfigDirs(appName string) []string {
+	return []string{
+		"${XDG_CONFIG_HOME}/" + appName,
+		"${HOME}/.config/" + appName,
+		"/etc/" + appName,
+		".",
+	}
+}
+
+// Find configuration file. Explicit path (for example value of '--config'
+// command line option) has the highest priority. Otherwise files
+// '<name>.<extension>' are searched in directories in the specified order.
+// Directories and name can contain placeholders like '${HOME}' which are
+// processed by ProcessString. Directory is skipped if its placeholder value is empty.
+// Example usage:
+//
+//	res, err := DiscoverConfigFile(*configFlag, "app", []string{"json", "yaml", "toml"}, DefaultConfigDirs("app"), nil)
+//	if err != nil {
+//	  return err
+//	}
+//	processor, err := LoadFile(res.Path, "$")
+//
+// Parameters:
+//   - explicitPath explicit path to the file. Not used if empty
+//   - name file name without extension
+//   - extensions list of extensions in priority order. Empty extension means file name without extension
+//   - dirs list of directories in priority order (see DefaultConfigDirs)
+//   - dictionary dictionary for placeholders processing
+//
+// Returns:
+//   - discovery result. Contains found file and skipped candidates
+//   - error if explicit file is not found or no file found in directories
+func DiscoverConfigFile(explicitPath string, name string, extensions []string, dirs []string, dictionary map[string]string) (*ConfigDiscovery, error) {
+	res := &ConfigDiscovery{Skipped: make([]ConfigCandidate, 0)}
+	if explicitPath != "" {
+		path, err := ProcessString(explicitPath, dictionary)
+		if err != nil {
+			return res, err
+		}
+		reason := checkConfigFile(path)
+		if reason != "" {
+			res.Skipped = append(res.Skipped, ConfigCandidate{Path: path, Reason: reason})
+			return res, fmt.Errorf("configuration file '%s' %s", path, reason)
+		}
+		res.Path = path
+		return res, nil
+	}
+	name, err := ProcessString(name, dictionary)
+	if err != nil {
+		return res, err
+	}
+	for _, dir := range dirs {
+		placeholder, err := findEmptyPlaceholder(dir, dictionary)
+		if err != nil {
+			res.Skipped = append(res.Skipped, ConfigCandidate{Path: dir, Reason: err.Error()})
+			continue
+		}
+		if placeholder != "" {
+			res.Skipped = append(res.Skipped, ConfigCandidate{Path: dir, Reason: "placeholder '" + placeholder + "' is empty"})
+			continue
+		}
+		dir, _ = ProcessString(dir, dictionary)
+		for _, ext := range extensions {
+			fileName := name
+			if ext != "" {
+				fileName = name + "." + strings.TrimPrefix(ext, ".")
+			}
+			path := filepath.Join(dir, fileName)
+			reason := checkConfigFile(path)
+			if reason == "" {
+				res.Path = path
+				return res, nil
+			}
+			res.Skipped = append(res.Skipped, ConfigCandidate{Path: path, Reason: reason})
+		}
+	}
+	return res, fmt.Errorf("configuration file '%s' %w", name, fs.ErrNotExist)
+}
+
+// Returns reason why file can't be used as configuration file or empty string.
+func checkConfigFile(path string) string {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "not found"
+	}
+	if err != nil {
+		return err.Error()
+	}
+	if info.IsDir() {
+		return "is a directory"
+	}
+	return ""
+}
+
+// Returns the first placeholder which value is empty.
+// Returns:
+//   - placeholder (like '${HOME}') or empty string if all placeholders have values
+//   - error if string has incorrect structure
+func findEmptyPlaceholder(str string, dictionary map[string]string) (string, error) {
+	for {
+		stIndx := strings.Index(str, START_INCLUDE)
+		if stIndx < 0 {
+			return "", nil
+		}
+		endIndx := findCloseBrace(str, stIndx+len(START_INCLUDE))
+		if endIndx < 0 {
+			_, err := ProcessString(str, dictionary)
+			return "", err
+		}
+		placeholder := str[stIndx : endIndx+len(END_INCLUDE)]
+		val, err := ProcessString(placeholder, dictionary)
+		if err != nil {
+			return "", err
+		}
+		if val == "" {
+			return placeholder, nil
+		}
+		str = str[endIndx+len(END_INCLUDE):]
+	}
+}
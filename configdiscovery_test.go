@@ -0,0 +1,71 @@
+package dynamictags
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultConfigDirs(t *testing.T) {
+	dirs := DefaultConfigDirs("app")
+	assert.Equal(t, []string{"${XDG_CONFIG_HOME}/app", "${HOME}/.config/app", "/etc/app", "."}, dirs)
+}
+
+func TestDiscoverConfigFile(t *testing.T) {
+	root := t.TempDir()
+	xdgDir := filepath.Join(root, "xdg")
+	homeDir := filepath.Join(root, "home")
+	err := os.MkdirAll(filepath.Join(homeDir, ".config", "app"), 0o700)
+	assert.NoError(t, err)
+	homeFile := filepath.Join(homeDir, ".config", "app", "app.yaml")
+	err = os.WriteFile(homeFile, []byte("a: 1"), 0o600)
+	assert.NoError(t, err)
+	// Directory with the same name as file is skipped
+	err = os.MkdirAll(filepath.Join(homeDir, ".config", "app", "app.json"), 0o700)
+	assert.NoError(t, err)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	dirs := []string{"${XDG_CONFIG_HOME}/app", "${DISCOVERY_HOME}/.config/app"}
+	dict := map[string]string{"DISCOVERY_HOME": homeDir}
+
+	// Case 1 file found in the second directory
+	res, err := DiscoverConfigFile("", "app", []string{"json", "yaml"}, dirs, dict)
+	assert.NoError(t, err)
+	assert.Equal(t, homeFile, res.Path)
+	assert.Equal(t, []ConfigCandidate{
+		{Path: "${XDG_CONFIG_HOME}/app", Reason: "placeholder '${XDG_CONFIG_HOME}' is empty"},
+		{Path: filepath.Join(homeDir, ".config", "app", "app.json"), Reason: "is a directory"},
+	}, res.Skipped)
+
+	// Case 2 file in the first directory has priority
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+	err = os.MkdirAll(filepath.Join(xdgDir, "app"), 0o700)
+	assert.NoError(t, err)
+	xdgFile := filepath.Join(xdgDir, "app", "app.json")
+	err = os.WriteFile(xdgFile, []byte("{}"), 0o600)
+	assert.NoError(t, err)
+	res, err = DiscoverConfigFile("", "app", []string{"json", "yaml"}, dirs, dict)
+	assert.NoError(t, err)
+	assert.Equal(t, xdgFile, res.Path)
+	assert.Empty(t, res.Skipped)
+
+	// Case 3 explicit path
+	res, err = DiscoverConfigFile("${DISCOVERY_HOME}/.config/app/app.yaml", "app", []string{"json"}, dirs, dict)
+	assert.NoError(t, err)
+	assert.Equal(t, homeFile, res.Path)
+
+	// Case 4 explicit path not exists
+	res, err = DiscoverConfigFile(filepath.Join(root, "none.json"), "app", []string{"json"}, dirs, dict)
+	assert.Error(t, err)
+	assert.Equal(t, "", res.Path)
+	assert.Equal(t, 1, len(res.Skipped))
+
+	// Case 5 file not found
+	res, err = DiscoverConfigFile("", "other", []string{"toml"}, dirs, dict)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+	assert.Equal(t, "", res.Path)
+	assert.Equal(t, 2, len(res.Skipped))
+}
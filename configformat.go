@@ -0,0 +1,239 @@
+package dynamictags
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Configuration content format
+type ConfigFormat string
+
+const (
+	FORMAT_UNKNOWN    ConfigFormat = ""
+	FORMAT_JSON       ConfigFormat = "json"
+	FORMAT_JSON5      ConfigFormat = "json5"
+	FORMAT_YAML       ConfigFormat = "yaml"
+	FORMAT_TOML       ConfigFormat = "toml"
+	FORMAT_INI        ConfigFormat = "ini"
+	FORMAT_PROPERTIES ConfigFormat = "properties"
+	FORMAT_DOTENV     ConfigFormat = "dotenv"
+	FORMAT_XML        ConfigFormat = "xml"
+)
+
+var formatExtensions = map[string]ConfigFormat{
+	".json":       FORMAT_JSON,
+	".jsonc":      FORMAT_JSON5,
+	".json5":      FORMAT_JSON5,
+	".yaml":       FORMAT_YAML,
+	".yml":        FORMAT_YAML,
+	".toml":       FORMAT_TOML,
+	".ini":        FORMAT_INI,
+	".cfg":        FORMAT_INI,
+	".properties": FORMAT_PROPERTIES,
+	".env":        FORMAT_DOTENV,
+	".xml":        FORMAT_XML,
+}
+
+var dotEnvLineRegexp = regexp.MustCompile(`^(export\s+)?[A-Za-z_][A-Za-z0-9_.]*=`)
+var yamlLineRegexp = regexp.MustCompile(`line (\d+)`)
+
+// Detect configuration format by file extension. If extension is unknown
+// format is detected by content.
+// Parameters:
+//   - filePath path to the file. Can be empty
+//   - content file content
+//
+// Returns:
+//   - detected format or FORMAT_UNKNOWN
+func DetectFormat(filePath string, content []byte) ConfigFormat {
+	base := path.Base(strings.ReplaceAll(filePath, "\\", "/"))
+	if base == ".env" || strings.HasPrefix(base, ".env.") {
+		return FORMAT_DOTENV
+	}
+	format, ok := formatExtensions[strings.ToLower(path.Ext(base))]
+	if ok {
+		return format
+	}
+	return sniffFormat(content)
+}
+
+func sniffFormat(content []byte) ConfigFormat {
+	content = bytes.TrimPrefix(content, []byte("\uFEFF"))
+	text := strings.TrimSpace(string(content))
+	if text == "" {
+		return FORMAT_UNKNOWN
+	}
+	switch {
+	case text[0] == '<':
+		return FORMAT_XML
+	case text[0] == '{' && json.Valid(content):
+		return FORMAT_JSON
+	case text[0] == '{':
+		return FORMAT_JSON5
+	case text[0] == '[' && json.Valid(content):
+		return FORMAT_JSON
+	case strings.HasPrefix(text, "---"):
+		return FORMAT_YAML
+	}
+	hasSection := false
+	isDotEnv := true
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			hasSection = true
+		}
+		if !dotEnvLineRegexp.MatchString(line) {
+			isDotEnv = false
+		}
+	}
+	if isDotEnv && !hasSection {
+		return FORMAT_DOTENV
+	}
+	_, err := ReadToml(content)
+	if err == nil {
+		return FORMAT_TOML
+	}
+	if hasSection {
+		return FORMAT_INI
+	}
+	docs, err := ReadYamlDocuments(content)
+	if err == nil && len(docs) > 0 {
+		_, ok := docs[0].(map[string]interface{})
+		if ok {
+			return FORMAT_YAML
+		}
+	}
+	return FORMAT_UNKNOWN
+}
+
+// Read tree (like result of json.Unmarshal) from content.
+// Parameters:
+//   - format content format. Only json, json5, yaml and toml formats are supported
+//   - content content
+//
+// Returns:
+//   - parsed tree
+//   - error in case of syntax error or unsupported format
+func readTree(format ConfigFormat, content []byte) (any, error) {
+	switch format {
+	case FORMAT_JSON:
+		var res any
+		err := json.Unmarshal(content, &res)
+		if err != nil {
+			var jsonErr *json.SyntaxError
+			if errors.As(err, &jsonErr) {
+				return nil, newSyntaxError(string(content), int(jsonErr.Offset), jsonErr.Error())
+			}
+			return nil, err
+		}
+		return res, nil
+	case FORMAT_JSON5:
+		return ReadJson5(content)
+	case FORMAT_YAML:
+		doc, err := ReadYamlDocument(content, 0)
+		if err != nil {
+			return nil, convertYamlError(err)
+		}
+		return doc, nil
+	case FORMAT_TOML:
+		return ReadToml(content)
+	}
+	return nil, errors.New("format '" + string(format) + "' is not a tree format")
+}
+
+// Convert yaml error to SyntaxError if it contains line number.
+func convertYamlError(err error) error {
+	match := yamlLineRegexp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(match[1])
+	return &SyntaxError{Line: line, Msg: err.Error()}
+}
+
+// Returns true if content of the format is parsed to tree.
+func isTreeFormat(format ConfigFormat) bool {
+	return format == FORMAT_JSON || format == FORMAT_JSON5 || format == FORMAT_YAML || format == FORMAT_TOML
+}
+
+// Create tag converter for parsed tree.
+func newTreeTagConverter(format ConfigFormat, tree any, rootPath string) (TagConverterer, error) {
+	switch format {
+	case FORMAT_YAML:
+		return NewYamlTagConverter(tree, rootPath)
+	case FORMAT_TOML:
+		return NewTomlTagConverter(tree, rootPath)
+	}
+	return NewJsonTagConverter(tree, rootPath)
+}
+
+// Returns values with keys started with rootPath. The prefix is removed from keys.
+func selectFlatRoot(values map[string]string, rootPath string) map[string]string {
+	prefix := composeFlatKey("", rootPath)
+	prefix = strings.TrimSuffix(prefix, ".")
+	if prefix == "" {
+		return values
+	}
+	res := make(map[string]string)
+	for key, val := range values {
+		if strings.HasPrefix(key, prefix+".") {
+			res[key[len(prefix)+1:]] = val
+		}
+	}
+	return res
+}
+
+// Create tag converter for the content.
+// Parameters:
+//   - format content format
+//   - content content
+//   - rootPath path to the root of processed structure. Json path for tree formats,
+//     XPath for xml and key prefix (like '$.database') for ini and properties.
+//     Not used for dotenv format
+//
+// Returns:
+//   - tag converter. Converter tag depends on format (json tag for json and json5 formats)
+//   - error in case of syntax error
+func NewFormatTagConverter(format ConfigFormat, content []byte, rootPath string) (TagConverterer, error) {
+	switch format {
+	case FORMAT_JSON, FORMAT_JSON5, FORMAT_YAML, FORMAT_TOML:
+		tree, err := readTree(format, content)
+		if err != nil {
+			return nil, err
+		}
+		return newTreeTagConverter(format, tree, rootPath)
+	case FORMAT_INI:
+		values, err := ReadIni(content)
+		if err != nil {
+			return nil, err
+		}
+		return NewIniTagConverter(selectFlatRoot(values, rootPath)), nil
+	case FORMAT_PROPERTIES:
+		values, err := ReadProperties(content)
+		if err != nil {
+			return nil, err
+		}
+		return NewPropertiesTagConverter(selectFlatRoot(values, rootPath)), nil
+	case FORMAT_DOTENV:
+		env, err := ReadDotEnv(content, false)
+		if err != nil {
+			return nil, err
+		}
+		return NewDotEnvTagConverter(env), nil
+	case FORMAT_XML:
+		doc, err := ReadXml(content)
+		if err != nil {
+			return nil, err
+		}
+		return NewXmlTagConverter(doc, rootPath)
+	}
+	return nil, errors.New("unknown configuration format")
+}
@@ -0,0 +1,62 @@
+package dynamictags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectFormatByExtension(t *testing.T) {
+	assert.Equal(t, FORMAT_JSON, DetectFormat("config.json", nil))
+	assert.Equal(t, FORMAT_JSON5, DetectFormat("config.JSONC", nil))
+	assert.Equal(t, FORMAT_YAML, DetectFormat("dir/config.yml", nil))
+	assert.Equal(t, FORMAT_TOML, DetectFormat("config.toml", nil))
+	assert.Equal(t, FORMAT_INI, DetectFormat("config.ini", nil))
+	assert.Equal(t, FORMAT_PROPERTIES, DetectFormat("application.properties", nil))
+	assert.Equal(t, FORMAT_DOTENV, DetectFormat("/app/.env", nil))
+	assert.Equal(t, FORMAT_DOTENV, DetectFormat(".env.local", nil))
+	assert.Equal(t, FORMAT_XML, DetectFormat("config.xml", nil))
+}
+
+func TestDetectFormatByContent(t *testing.T) {
+	assert.Equal(t, FORMAT_JSON, DetectFormat("config", []byte(`{"a": 1}`)))
+	assert.Equal(t, FORMAT_JSON, DetectFormat("config", []byte(`[1, 2]`)))
+	assert.Equal(t, FORMAT_JSON5, DetectFormat("config", []byte("{a: 1, // comment\n}")))
+	assert.Equal(t, FORMAT_YAML, DetectFormat("config", []byte("server:\n  port: 8080\n")))
+	assert.Equal(t, FORMAT_YAML, DetectFormat("config", []byte("---\na: 1")))
+	assert.Equal(t, FORMAT_TOML, DetectFormat("config", []byte("title = \"test\"\n[server]\nport = 8080")))
+	assert.Equal(t, FORMAT_INI, DetectFormat("config", []byte("; comment\n[server]\nport = 8080\nhost = localhost")))
+	assert.Equal(t, FORMAT_DOTENV, DetectFormat("config", []byte("# comment\nexport PORT=8080\nHOST=localhost")))
+	assert.Equal(t, FORMAT_XML, DetectFormat("config", []byte("<config/>")))
+	assert.Equal(t, FORMAT_UNKNOWN, DetectFormat("config", []byte("")))
+	assert.Equal(t, FORMAT_UNKNOWN, DetectFormat("config", []byte("plain text")))
+}
+
+func TestNewFormatTagConverter(t *testing.T) {
+	// Case 1 flat format with root path
+	conv, err := NewFormatTagConverter(FORMAT_INI, []byte("[server]\nport = 8080"), "$.server")
+	assert.NoError(t, err)
+	assert.Equal(t, INI_TAG, conv.GetTag())
+	val, isSet, err := conv.GetSimpleValue("port", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, "8080", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 json5 content is processed by json tag
+	conv, err = NewFormatTagConverter(FORMAT_JSON5, []byte("{server: {port: 8080}}"), "$.server")
+	assert.NoError(t, err)
+	assert.Equal(t, JSON_TAG, conv.GetTag())
+	// Case 3 json syntax error
+	_, err = NewFormatTagConverter(FORMAT_JSON, []byte("{\n\"a\": 1,\n}"), "$")
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, syntaxErr.Line)
+	// Case 4 yaml syntax error
+	_, err = NewFormatTagConverter(FORMAT_YAML, []byte("a: 1\nb: 2\n\tc: 3\n"), "$")
+	syntaxErr, ok = err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, syntaxErr.Line)
+	// Case 5 unknown format
+	_, err = NewFormatTagConverter(FORMAT_UNKNOWN, []byte(""), "$")
+	assert.Error(t, err)
+}
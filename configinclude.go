@@ -0,0 +1,232 @@
+package dynamictags
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	INCLUDE_KEY = "$include"
+)
+
+// Read configuration tree from file and process include directives.
+// Object can include other files by '$include' key. Value is path or list of
+// paths. Relative paths are resolved against the including file directory.
+// Paths can contain placeholders processed by ProcessString. Included files
+// are deep merged in the specified order (see DeepMerge) and the object
+// values are merged on top of them. For example:
+//
+//	{
+//	  "$include": ["base.json", "${ENV}.yaml"],
+//	  "database": {"port": 5432}
+//	}
+//
+// Files can have any tree format (json, json5, yaml or toml).
+// Parameters:
+//   - filePath path to the file
+//   - dictionary dictionary for placeholders processing
+//
+// Returns:
+//   - configuration tree which can be passed to NewJsonTagConverter
+//   - error if file can't be read, has syntax error or include cycle is found
+func ReadTreeFile(filePath string, dictionary map[string]string) (any, error) {
+	resolver := newOsIncludeResolver(dictionary)
+	return resolver.load(filePath)
+}
+
+// Read configuration tree from file system and process include directives.
+// The same as ReadTreeFile but files are read from fsys.
+// Parameters:
+//   - fsys file system
+//   - filePath path to the file in the file system
+//   - dictionary dictionary for placeholders processing
+//
+// Returns:
+//   - configuration tree which can be passed to NewJsonTagConverter
+//   - error if file can't be read, has syntax error or include cycle is found
+func ReadTreeFS(fsys fs.FS, filePath string, dictionary map[string]string) (any, error) {
+	resolver := newFSIncludeResolver(fsys, dictionary)
+	return resolver.load(filePath)
+}
+
+// Deep merge of two configuration trees. Objects are merged recursively,
+// other values (including arrays) of overlay replace base values. Source
+// trees are not modified.
+// Parameters:
+//   - base base tree
+//   - overlay overlay tree
+//
+// Returns:
+//   - merged tree
+func DeepMerge(base any, overlay any) any {
+	baseMap, ok := base.(map[string]interface{})
+	if !ok {
+		return overlay
+	}
+	overlayMap, ok := overlay.(map[string]interface{})
+	if !ok {
+		return overlay
+	}
+	res := make(map[string]interface{}, len(baseMap)+len(overlayMap))
+	for key, val := range baseMap {
+		res[key] = val
+	}
+	for key, val := range overlayMap {
+		baseVal, ok := res[key]
+		if ok {
+			res[key] = DeepMerge(baseVal, val)
+		} else {
+			res[key] = val
+		}
+	}
+	return res
+}
+
+type includeResolver struct {
+	readFile   func(name string) ([]byte, error)
+	join       func(base string, name string) string
+	clean      func(name string) string
+	dictionary map[string]string
+	// Files which are currently loaded. Used to detect include cycles
+	stack []string
+}
+
+func newOsIncludeResolver(dictionary map[string]string) *includeResolver {
+	return &includeResolver{
+		readFile: os.ReadFile,
+		join: func(base string, name string) string {
+			if filepath.IsAbs(name) {
+				return name
+			}
+			return filepath.Join(filepath.Dir(base), name)
+		},
+		clean:      filepath.Clean,
+		dictionary: dictionary,
+	}
+}
+
+func newFSIncludeResolver(fsys fs.FS, dictionary map[string]string) *includeResolver {
+	return &includeResolver{
+		readFile: func(name string) ([]byte, error) {
+			return fs.ReadFile(fsys, name)
+		},
+		join: func(base string, name string) string {
+			if strings.HasPrefix(name, "/") {
+				return strings.TrimPrefix(path.Clean(name), "/")
+			}
+			return path.Join(path.Dir(base), name)
+		},
+		clean:      path.Clean,
+		dictionary: dictionary,
+	}
+}
+
+// Read and parse file, process includes.
+func (resolver *includeResolver) load(filePath string) (any, error) {
+	filePath = resolver.clean(filePath)
+	content, err := resolver.readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return resolver.loadContent(filePath, DetectFormat(filePath, content), content)
+}
+
+// Parse content, process includes.
+func (resolver *includeResolver) loadContent(filePath string, format ConfigFormat, content []byte) (any, error) {
+	// Included paths are cleaned by join, so root path is cleaned too to
+	// detect cycles
+	filePath = resolver.clean(filePath)
+	if slices.Contains(resolver.stack, filePath) {
+		cycle := strings.Join(append(resolver.stack, filePath), " -> ")
+		return nil, errors.New("include cycle: " + cycle)
+	}
+	tree, err := readTree(format, content)
+	if err != nil {
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.File == "" {
+			syntaxErr.File = filePath
+		}
+		return nil, err
+	}
+	resolver.stack = append(resolver.stack, filePath)
+	defer func() {
+		resolver.stack = resolver.stack[:len(resolver.stack)-1]
+	}()
+	return resolver.resolve(filePath, tree)
+}
+
+func (resolver *includeResolver) resolve(filePath string, tree any) (any, error) {
+	switch val := tree.(type) {
+	case map[string]interface{}:
+		var base any
+		includes, ok := val[INCLUDE_KEY]
+		if ok {
+			delete(val, INCLUDE_KEY)
+			names, err := getIncludeNames(includes)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", filePath, err)
+			}
+			for _, name := range names {
+				name, err = ProcessString(name, resolver.dictionary)
+				if err != nil {
+					return nil, err
+				}
+				if name == "" {
+					return nil, errors.New(filePath + ": include path is empty")
+				}
+				included, err := resolver.load(resolver.join(filePath, name))
+				if err != nil {
+					return nil, err
+				}
+				base = DeepMerge(base, included)
+			}
+		}
+		for key, item := range val {
+			res, err := resolver.resolve(filePath, item)
+			if err != nil {
+				return nil, err
+			}
+			val[key] = res
+		}
+		if base != nil {
+			return DeepMerge(base, val), nil
+		}
+		return val, nil
+	case []interface{}:
+		for i, item := range val {
+			res, err := resolver.resolve(filePath, item)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = res
+		}
+		return val, nil
+	}
+	return tree, nil
+}
+
+func getIncludeNames(includes any) ([]string, error) {
+	name, ok := includes.(string)
+	if ok {
+		return []string{name}, nil
+	}
+	list, ok := includes.([]interface{})
+	if !ok {
+		return nil, errors.New("'" + INCLUDE_KEY + "' should be string or list of strings")
+	}
+	res := make([]string, 0, len(list))
+	for _, item := range list {
+		name, ok := item.(string)
+		if !ok {
+			return nil, errors.New("'" + INCLUDE_KEY + "' should be string or list of strings")
+		}
+		res = append(res, name)
+	}
+	return res, nil
+}
@@ -0,0 +1,106 @@
+package dynamictags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepMerge(t *testing.T) {
+	base := map[string]interface{}{
+		"a": map[string]interface{}{"x": 1.0, "y": 2.0},
+		"b": []interface{}{1.0, 2.0},
+		"c": "base",
+	}
+	overlay := map[string]interface{}{
+		"a": map[string]interface{}{"y": 3.0, "z": 4.0},
+		"b": []interface{}{5.0},
+		"d": "overlay",
+	}
+	res := DeepMerge(base, overlay)
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"x": 1.0, "y": 3.0, "z": 4.0},
+		"b": []interface{}{5.0},
+		"c": "base",
+		"d": "overlay",
+	}, res)
+	// Base is not modified
+	assert.Equal(t, 2.0, base["a"].(map[string]interface{})["y"])
+	// Not object values are replaced
+	assert.Equal(t, "value", DeepMerge(base, "value"))
+	assert.Equal(t, overlay, DeepMerge(nil, overlay))
+}
+
+func TestReadTreeFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/base.json": &fstest.MapFile{Data: []byte(`{"database": {"host": "localhost", "port": 5432}, "debug": false}`)},
+		"config/prod.yaml": &fstest.MapFile{Data: []byte("database:\n  host: db.prod\n")},
+		"config/app.json": &fstest.MapFile{Data: []byte(`{
+			"$include": ["base.json", "${INCLUDE_ENV}.yaml"],
+			"debug": true,
+			"logging": {"$include": "/shared/logging.toml"}
+		}`)},
+		"shared/logging.toml": &fstest.MapFile{Data: []byte("level = \"info\"")},
+		"cycle/a.json":        &fstest.MapFile{Data: []byte(`{"$include": "b.json"}`)},
+		"cycle/b.json":        &fstest.MapFile{Data: []byte(`{"$include": "a.json"}`)},
+		"broken/a.json":       &fstest.MapFile{Data: []byte(`{"$include": "b.toml"}`)},
+		"broken/b.toml":       &fstest.MapFile{Data: []byte("a = ")},
+		"wrong/a.json":        &fstest.MapFile{Data: []byte(`{"$include": 1}`)},
+	}
+	// Case 1 includes
+	tree, err := ReadTreeFS(fsys, "config/app.json", map[string]string{"INCLUDE_ENV": "prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"database": map[string]interface{}{"host": "db.prod", "port": 5432.0},
+		"debug":    true,
+		"logging":  map[string]interface{}{"level": "info"},
+	}, tree)
+	// Case 2 include cycle
+	_, err = ReadTreeFS(fsys, "cycle/a.json", nil)
+	assert.ErrorContains(t, err, "cycle/a.json -> cycle/b.json -> cycle/a.json")
+	_, err = ReadTreeFS(fsys, "./cycle/../cycle/a.json", nil)
+	assert.EqualError(t, err, "include cycle: cycle/a.json -> cycle/b.json -> cycle/a.json")
+	// Case 3 syntax error in included file
+	_, err = ReadTreeFS(fsys, "broken/a.json", nil)
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, "broken/b.toml", syntaxErr.File)
+	// Case 4 incorrect include value
+	_, err = ReadTreeFS(fsys, "wrong/a.json", nil)
+	assert.Error(t, err)
+	// Case 5 included file not exists
+	_, err = ReadTreeFS(fsys, "config/app.json", map[string]string{"INCLUDE_ENV": "dev"})
+	assert.Error(t, err)
+}
+
+func TestReadTreeFile(t *testing.T) {
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "env"), 0o700)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "base.json"), []byte(`{"port": 8080, "host": "localhost"}`), 0o600)
+	assert.NoError(t, err)
+	appFile := filepath.Join(dir, "env", "app.json")
+	err = os.WriteFile(appFile, []byte(`{"$include": "../base.json", "port": 9090}`), 0o600)
+	assert.NoError(t, err)
+	// Case 1 relative include
+	tree, err := ReadTreeFile(appFile, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"port": 9090.0, "host": "localhost"}, tree)
+	// Case 2 LoadFile processes includes
+	processor, err := LoadFile(appFile, "$")
+	assert.NoError(t, err)
+	testStruct := LoaderTestStruct{}
+	err = processor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", testStruct.Host)
+	assert.Equal(t, 9090, testStruct.Port)
+	// Case 3 cycle with not clean root path
+	cycleFile := filepath.Join(dir, "cycle.json")
+	err = os.WriteFile(cycleFile, []byte(`{"$include": "cycle.json"}`), 0o600)
+	assert.NoError(t, err)
+	_, err = ReadTreeFile(dir+"/./cycle.json", nil)
+	assert.EqualError(t, err, "include cycle: "+cycleFile+" -> "+cycleFile)
+}
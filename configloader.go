@@ -0,0 +1,94 @@
+package dynamictags
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// Load configuration file and create configuration processor. File format
+// is detected by extension or by content (see DetectFormat). Processor
+// process tag of the file format (for example 'yaml' tag for yaml file),
+// 'env' and 'default' tags. Include directives of json, json5, yaml and toml
+// files are processed (see ReadTreeFile). For these formats profile overlay
+// of the processing profile is applied (see DynamicTagProcessor.SetProfile).
+// Example usage:
+//
+//	processor, err := LoadFile("serverconfiguration.yaml", "$.database")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&databaseConfiguration, nil)
+//
+// Parameters:
+//   - path path to the file
+//   - rootPath path to the root of processed structure (see NewFormatTagConverter)
+//
+// Returns:
+//   - configuration processor if success
+//   - error if file can't be read or has syntax error (*SyntaxError with
+//     line and column of the error)
+func LoadFile(path string, rootPath string) (*DynamicTagProcessor, error) {
+	return loadConfig(newOsIncludeResolver(nil), path, rootPath)
+}
+
+// Load configuration file from file system and create configuration processor.
+// The same as LoadFile with '$' root path but file is read from fsys. For
+// example embed.FS can be used for built-in default configuration.
+// Parameters:
+//   - fsys file system
+//   - path path to the file in the file system
+//
+// Returns:
+//   - configuration processor if success
+//   - error if file can't be read or has syntax error
+func LoadFS(fsys fs.FS, path string) (*DynamicTagProcessor, error) {
+	return LoadFSWithRoot(fsys, path, "$")
+}
+
+// Load configuration file from file system and create configuration processor.
+// The same as LoadFS but processed structure is bound to rootPath.
+// Parameters:
+//   - fsys file system
+//   - path path to the file in the file system
+//   - rootPath path to the root of processed structure (see NewFormatTagConverter)
+//
+// Returns:
+//   - configuration processor if success
+//   - error if file can't be read or has syntax error
+func LoadFSWithRoot(fsys fs.FS, path string, rootPath string) (*DynamicTagProcessor, error) {
+	return loadConfig(newFSIncludeResolver(fsys, nil), path, rootPath)
+}
+
+func loadConfig(resolver *includeResolver, path string, rootPath string) (*DynamicTagProcessor, error) {
+	content, err := resolver.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format := DetectFormat(path, content)
+	if format == FORMAT_UNKNOWN {
+		return nil, errors.New("unknown format of configuration file '" + path + "'")
+	}
+	var converter TagConverterer
+	if isTreeFormat(format) {
+		var tree any
+		tree, err = resolver.loadContent(path, format, content)
+		if err == nil {
+			converter, err = newTreeTagConverter(format, tree, rootPath)
+		}
+	} else {
+		converter, err = NewFormatTagConverter(format, content, rootPath)
+	}
+	if err != nil {
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.File == "" {
+			syntaxErr.File = path
+		}
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(converter)
+	processor.AddTagConverter(NewEnvTagConverter())
+	processor.AddTagConverter(NewDefaultTagConverter())
+	return &processor, nil
+}
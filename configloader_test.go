@@ -0,0 +1,84 @@
+package dynamictags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type LoaderTestStruct struct {
+	Host string `yaml:"host" toml:"host" json:"host" default:"localhost"`
+	Port int    `yaml:"port" toml:"port" json:"port" env:"LOADER_TEST_PORT"`
+}
+
+type LoaderRootTestStruct struct {
+	Database LoaderTestStruct `toml:"database"`
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	err := os.WriteFile(path, []byte("database:\n  port: 5432\n"), 0o600)
+	assert.NoError(t, err)
+	// Case 1 yaml file
+	processor, err := LoadFile(path, "$.database")
+	assert.NoError(t, err)
+	testStruct := LoaderTestStruct{}
+	err = processor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", testStruct.Host)
+	assert.Equal(t, 5432, testStruct.Port)
+	// Case 2 file not exists
+	_, err = LoadFile(filepath.Join(dir, "unknown.yaml"), "$")
+	assert.Error(t, err)
+	// Case 3 syntax error
+	path = filepath.Join(dir, "config.toml")
+	err = os.WriteFile(path, []byte("[database]\nport = 5432\nhost = \"db"), 0o600)
+	assert.NoError(t, err)
+	_, err = LoadFile(path, "$")
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, path, syntaxErr.File)
+	assert.Equal(t, 3, syntaxErr.Line)
+	assert.Equal(t, 8, syntaxErr.Column)
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"defaults/config.toml": &fstest.MapFile{Data: []byte("[database]\nhost = \"db.local\"\nport = 5432")},
+		"defaults/config":      &fstest.MapFile{Data: []byte(`{"database": {"port": 6543}}`)},
+		"defaults/unknown":     &fstest.MapFile{Data: []byte("plain text")},
+	}
+	// Case 1 format by extension
+	processor, err := LoadFSWithRoot(fsys, "defaults/config.toml", "$.database")
+	assert.NoError(t, err)
+	testStruct := LoaderTestStruct{}
+	err = processor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "db.local", testStruct.Host)
+	assert.Equal(t, 5432, testStruct.Port)
+	// Case 2 format by content. Environment variable has lower priority than file value
+	os.Setenv("LOADER_TEST_PORT", "7777")
+	defer os.Unsetenv("LOADER_TEST_PORT")
+	processor, err = LoadFSWithRoot(fsys, "defaults/config", "$.database")
+	assert.NoError(t, err)
+	testStruct = LoaderTestStruct{}
+	err = processor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", testStruct.Host)
+	assert.Equal(t, 6543, testStruct.Port)
+	// Case 3 unknown format
+	_, err = LoadFS(fsys, "defaults/unknown")
+	assert.Error(t, err)
+	// Case 4 document root
+	processor, err = LoadFS(fsys, "defaults/config.toml")
+	assert.NoError(t, err)
+	rootStruct := LoaderRootTestStruct{}
+	err = processor.Process(&rootStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "db.local", rootStruct.Database.Host)
+	assert.Equal(t, 5432, rootStruct.Database.Port)
+}
@@ -0,0 +1,207 @@
+package dynamictags
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+const (
+	SIGNATURE_KEY         = "$signature"
+	SIGNATURE_FILE_SUFFIX = ".sig"
+)
+
+// Generate ed25519 signing key pair.
+// Returns:
+//   - base64 encoded public key. Public key is used for verification
+//   - base64 encoded private key. Private key is used for signing
+//   - error in case of error
+func GenerateSigningKey() (string, string, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(publicKey), base64.StdEncoding.EncodeToString(privateKey), nil
+}
+
+// Parse base64 encoded ed25519 public key.
+// Parameters:
+//   - encodedKey base64 encoded key
+//
+// Returns:
+//   - public key
+//   - error if key is incorrect
+func ParsePublicKey(encodedKey string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("incorrect ed25519 public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Parse base64 encoded ed25519 private key.
+// Parameters:
+//   - encodedKey base64 encoded key
+//
+// Returns:
+//   - private key
+//   - error if key is incorrect
+func ParsePrivateKey(encodedKey string) (ed25519.PrivateKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("incorrect ed25519 private key")
+	}
+	return ed25519.PrivateKey(key), nil
+}
+
+// Sign configuration content. Signature is detached, i.e. it should be
+// stored separately (for example in '<file>.sig' file).
+// Parameters:
+//   - content configuration content
+//   - privateKey private key
+//
+// Returns:
+//   - base64 encoded signature
+func SignConfig(content []byte, privateKey ed25519.PrivateKey) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, content))
+}
+
+// Verify detached signature of configuration content.
+// Parameters:
+//   - content configuration content
+//   - signature base64 encoded signature
+//   - trustedKeys trusted public keys. Signature of any key is accepted
+//
+// Returns:
+//   - error if signature is incorrect
+func VerifyConfig(content []byte, signature string, trustedKeys []ed25519.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return errors.New("incorrect signature format")
+	}
+	for _, key := range trustedKeys {
+		if len(key) == ed25519.PublicKeySize && ed25519.Verify(key, content, sig) {
+			return nil
+		}
+	}
+	return errors.New("configuration signature is not valid")
+}
+
+// Sign configuration tree. Signature of canonical json of the tree (keys
+// are sorted, no whitespaces) is embedded into the root object as
+// '$signature' key. So the tree can be converted to other format (like
+// yaml) without signature change.
+// Parameters:
+//   - tree configuration tree. Root should be an object
+//   - privateKey private key
+//
+// Returns:
+//   - signed tree. Source tree is not modified
+//   - error in case of error
+func SignConfigTree(tree any, privateKey ed25519.PrivateKey) (any, error) {
+	unsigned, _, err := splitSignature(tree)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	unsigned[SIGNATURE_KEY] = SignConfig(content, privateKey)
+	return unsigned, nil
+}
+
+// Verify signature embedded into configuration tree (see SignConfigTree).
+// Parameters:
+//   - tree configuration tree
+//   - trustedKeys trusted public keys
+//
+// Returns:
+//   - tree without signature. Can be passed to NewJsonTagConverter
+//   - error if tree is not signed or signature is incorrect
+func VerifyConfigTree(tree any, trustedKeys []ed25519.PublicKey) (any, error) {
+	unsigned, signature, err := splitSignature(tree)
+	if err != nil {
+		return nil, err
+	}
+	if signature == "" {
+		return nil, errors.New("configuration is not signed")
+	}
+	content, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	err = VerifyConfig(content, signature, trustedKeys)
+	if err != nil {
+		return nil, err
+	}
+	return unsigned, nil
+}
+
+// Returns copy of the root object without signature and the signature.
+func splitSignature(tree any) (map[string]interface{}, string, error) {
+	treeMap, ok := tree.(map[string]interface{})
+	if !ok {
+		return nil, "", errors.New("configuration root should be an object")
+	}
+	res := make(map[string]interface{}, len(treeMap))
+	for key, val := range treeMap {
+		res[key] = val
+	}
+	signature, _ := res[SIGNATURE_KEY].(string)
+	delete(res, SIGNATURE_KEY)
+	return res, signature, nil
+}
+
+// Read signed json file. If '<file>.sig' file exists file content is
+// verified by the detached signature. Otherwise signature embedded into the
+// root object is verified (see SignConfigTree). Not signed or tampered file
+// is refused. Example usage:
+//
+//	tree, err := ReadSignedJsonFile("config.json", trustedKeys)
+//	if err != nil {
+//	  return err
+//	}
+//	processor, err := NewJsonProcessor(tree, "$")
+//
+// Parameters:
+//   - filePath path to the file
+//   - trustedKeys trusted public keys
+//
+// Returns:
+//   - configuration tree without signature
+//   - error if file can't be read or signature is incorrect
+func ReadSignedJsonFile(filePath string, trustedKeys []ed25519.PublicKey) (any, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := os.ReadFile(filePath + SIGNATURE_FILE_SUFFIX)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	isDetached := err == nil
+	if isDetached {
+		err = VerifyConfig(content, string(signature), trustedKeys)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tree, err := readTree(FORMAT_JSON, content)
+	if err != nil {
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) {
+			syntaxErr.File = filePath
+		}
+		return nil, err
+	}
+	if isDetached {
+		return tree, nil
+	}
+	return VerifyConfigTree(tree, trustedKeys)
+}
@@ -0,0 +1,133 @@
+package dynamictags
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	SIGNED_CONFIG = `{"database": {"host": "localhost", "port": 5432}}`
+)
+
+type SignatureTestStruct struct {
+	Host string `json:"database.host"`
+	Port int    `json:"database.port"`
+}
+
+func generateTestSigningKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	publicStr, privateStr, err := GenerateSigningKey()
+	assert.Nil(t, err)
+	publicKey, err := ParsePublicKey(publicStr)
+	assert.Nil(t, err)
+	privateKey, err := ParsePrivateKey(privateStr)
+	assert.Nil(t, err)
+	return publicKey, privateKey
+}
+
+func TestSigningKeys(t *testing.T) {
+	// Case 1. Incorrect keys
+	_, err := ParsePublicKey("not a key")
+	assert.NotNil(t, err)
+	_, err = ParsePrivateKey("c2hvcnQ=")
+	assert.NotNil(t, err)
+	// Case 2. Generated keys
+	publicKey, privateKey := generateTestSigningKey(t)
+	assert.Equal(t, privateKey.Public(), publicKey)
+}
+
+func TestVerifyConfig(t *testing.T) {
+	publicKey, privateKey := generateTestSigningKey(t)
+	otherKey, _ := generateTestSigningKey(t)
+	signature := SignConfig([]byte(SIGNED_CONFIG), privateKey)
+	// Case 1. Any trusted key is accepted
+	err := VerifyConfig([]byte(SIGNED_CONFIG), signature, []ed25519.PublicKey{otherKey, publicKey})
+	assert.Nil(t, err)
+	// Case 2. Not trusted key
+	err = VerifyConfig([]byte(SIGNED_CONFIG), signature, []ed25519.PublicKey{otherKey})
+	assert.NotNil(t, err)
+	// Case 3. Tampered content
+	err = VerifyConfig([]byte(`{"database": {"host": "evil", "port": 5432}}`), signature, []ed25519.PublicKey{publicKey})
+	assert.NotNil(t, err)
+	// Case 4. Incorrect signature
+	err = VerifyConfig([]byte(SIGNED_CONFIG), "not a signature", []ed25519.PublicKey{publicKey})
+	assert.NotNil(t, err)
+}
+
+func TestVerifyConfigTree(t *testing.T) {
+	publicKey, privateKey := generateTestSigningKey(t)
+	var tree any
+	err := json.Unmarshal([]byte(SIGNED_CONFIG), &tree)
+	assert.Nil(t, err)
+	// Case 1. Signed tree
+	signed, err := SignConfigTree(tree, privateKey)
+	assert.Nil(t, err)
+	assert.NotContains(t, tree, SIGNATURE_KEY)
+	assert.Contains(t, signed, SIGNATURE_KEY)
+	res, err := VerifyConfigTree(signed, []ed25519.PublicKey{publicKey})
+	assert.Nil(t, err)
+	assert.Equal(t, tree, res)
+	// Case 2. Signature doesn't depend on formatting
+	content, err := json.MarshalIndent(signed, "", "    ")
+	assert.Nil(t, err)
+	var reformatted any
+	err = json.Unmarshal(content, &reformatted)
+	assert.Nil(t, err)
+	_, err = VerifyConfigTree(reformatted, []ed25519.PublicKey{publicKey})
+	assert.Nil(t, err)
+	// Case 3. Tampered tree
+	reformatted.(map[string]interface{})["database"].(map[string]interface{})["host"] = "evil"
+	_, err = VerifyConfigTree(reformatted, []ed25519.PublicKey{publicKey})
+	assert.NotNil(t, err)
+	// Case 4. Not signed tree
+	_, err = VerifyConfigTree(tree, []ed25519.PublicKey{publicKey})
+	assert.NotNil(t, err)
+	// Case 5. Root is not an object
+	_, err = SignConfigTree([]interface{}{"a"}, privateKey)
+	assert.NotNil(t, err)
+}
+
+func TestReadSignedJsonFile(t *testing.T) {
+	publicKey, privateKey := generateTestSigningKey(t)
+	trusted := []ed25519.PublicKey{publicKey}
+	dir := t.TempDir()
+	// Case 1. Detached signature
+	detachedPath := filepath.Join(dir, "detached.json")
+	os.WriteFile(detachedPath, []byte(SIGNED_CONFIG), 0644)
+	os.WriteFile(detachedPath+SIGNATURE_FILE_SUFFIX, []byte(SignConfig([]byte(SIGNED_CONFIG), privateKey)+"\n"), 0644)
+	tree, err := ReadSignedJsonFile(detachedPath, trusted)
+	assert.Nil(t, err)
+	processor, err := NewJsonProcessor(tree, "$")
+	assert.Nil(t, err)
+	data := SignatureTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, SignatureTestStruct{Host: "localhost", Port: 5432}, data)
+	// Case 2. Tampered file with detached signature
+	os.WriteFile(detachedPath, []byte(`{"database": {"host": "evil", "port": 5432}}`), 0644)
+	_, err = ReadSignedJsonFile(detachedPath, trusted)
+	assert.NotNil(t, err)
+	// Case 3. Embedded signature
+	var unsigned any
+	json.Unmarshal([]byte(SIGNED_CONFIG), &unsigned)
+	signed, err := SignConfigTree(unsigned, privateKey)
+	assert.Nil(t, err)
+	content, _ := json.Marshal(signed)
+	embeddedPath := filepath.Join(dir, "embedded.json")
+	os.WriteFile(embeddedPath, content, 0644)
+	tree, err = ReadSignedJsonFile(embeddedPath, trusted)
+	assert.Nil(t, err)
+	assert.Equal(t, unsigned, tree)
+	// Case 4. Not signed file
+	notSignedPath := filepath.Join(dir, "notsigned.json")
+	os.WriteFile(notSignedPath, []byte(SIGNED_CONFIG), 0644)
+	_, err = ReadSignedJsonFile(notSignedPath, trusted)
+	assert.NotNil(t, err)
+	// Case 5. Absent file
+	_, err = ReadSignedJsonFile(filepath.Join(dir, "absent.json"), trusted)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,251 @@
+package dynamictags
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CONSUL_DEFAULT_ADDRESS = "http://127.0.0.1:8500"
+	CONSUL_ADDRESS_ENV     = "CONSUL_HTTP_ADDR"
+	CONSUL_TOKEN_ENV       = "CONSUL_HTTP_TOKEN"
+	// Wait time of blocking query if wait is not specified
+	CONSUL_DEFAULT_WAIT = 5 * time.Minute
+)
+
+// Client of Consul compatible key/value http API ('/v1/kv/').
+type ConsulClient struct {
+	address string
+	token   string
+	client  *http.Client
+	timeout time.Duration
+}
+
+// Entry of Consul key/value API response.
+type consulEntry struct {
+	Key   string
+	Value *string
+}
+
+// Create Consul key/value client.
+// Parameters:
+//   - address server address (like 'http://127.0.0.1:8500'). If empty
+//     'CONSUL_HTTP_ADDR' environment variable or default address is used.
+//     Token is get from 'CONSUL_HTTP_TOKEN' environment variable
+//
+// Returns:
+//   - Consul client
+func NewConsulClient(address string) *ConsulClient {
+	if address == "" {
+		address = getEnvDefault(CONSUL_ADDRESS_ENV, CONSUL_DEFAULT_ADDRESS)
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return &ConsulClient{
+		address: strings.TrimSuffix(address, "/"),
+		token:   getEnvDefault(CONSUL_TOKEN_ENV, ""),
+		client:  &http.Client{},
+		timeout: HTTP_DEFAULT_TIMEOUT,
+	}
+}
+
+// Set ACL token.
+// Parameters:
+//   - token token
+func (client *ConsulClient) SetToken(token string) {
+	client.token = token
+}
+
+// Set http client. Timeout of the client is applied to all requests
+// including blocking queries, so it should be 0 (see SetTimeout).
+// Parameters:
+//   - httpClient http client
+func (client *ConsulClient) SetClient(httpClient *http.Client) {
+	client.client = httpClient
+}
+
+// Set request timeout. Blocking queries (see Watch) have timeout equal to
+// wait time plus this timeout.
+// Parameters:
+//   - timeout timeout of one request
+func (client *ConsulClient) SetTimeout(timeout time.Duration) {
+	client.timeout = timeout
+}
+
+// Returns all values under the prefix.
+// Parameters:
+//   - prefix keys prefix (like 'config/myapp')
+//
+// Returns:
+//   - values. Keys are relative to the prefix (like 'database/port')
+//   - index of the data. Can be passed to Watch
+//   - error in case of error
+func (client *ConsulClient) List(prefix string) (map[string]string, uint64, error) {
+	return client.list(context.Background(), prefix, 0, 0)
+}
+
+// Wait for changes of values under the prefix (blocking query). Returns
+// when values are changed, wait time expired or context is cancelled.
+// Example usage:
+//
+//	values, index, err := client.List("config/myapp")
+//	for err == nil {
+//	  values, index, err = client.Watch(ctx, "config/myapp", index, 5*time.Minute)
+//	  ...
+//	}
+//
+// Parameters:
+//   - ctx context
+//   - prefix keys prefix
+//   - index index returned by List or previous Watch
+//   - wait maximum wait time. If 0 server default is used
+//
+// Returns:
+//   - values. Keys are relative to the prefix
+//   - new index. The same index is returned if wait time is expired
+//   - error in case of error
+func (client *ConsulClient) Watch(ctx context.Context, prefix string, index uint64, wait time.Duration) (map[string]string, uint64, error) {
+	values, newIndex, err := client.list(ctx, prefix, index, wait)
+	if err != nil {
+		return nil, index, err
+	}
+	// Index can go backward (for example after snapshot restore)
+	if newIndex < index {
+		newIndex = 0
+	}
+	return values, newIndex, nil
+}
+
+// Returns value of the key.
+// Parameters:
+//   - ctx context
+//   - key key (like 'config/myapp/database/port')
+//
+// Returns:
+//   - value
+//   - false if key doesn't exist
+//   - error in case of error
+func (client *ConsulClient) Get(ctx context.Context, key string) (string, bool, error) {
+	key = strings.Trim(key, "/")
+	entries, _, err := client.request(ctx, key, url.Values{}, client.timeout)
+	if err != nil {
+		return "", false, err
+	}
+	for _, entry := range entries {
+		if entry.Key == key {
+			value, err := entry.decodeValue()
+			return value, err == nil, err
+		}
+	}
+	return "", false, nil
+}
+
+func (client *ConsulClient) list(ctx context.Context, prefix string, index uint64, wait time.Duration) (map[string]string, uint64, error) {
+	prefix = strings.Trim(prefix, "/")
+	query := url.Values{}
+	query.Set("recurse", "true")
+	timeout := client.timeout
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		// Server waits up to wait time plus wait/16 random jitter
+		blockingWait := wait
+		if blockingWait <= 0 {
+			blockingWait = CONSUL_DEFAULT_WAIT
+		}
+		timeout += blockingWait + blockingWait/16
+	}
+	if wait > 0 {
+		query.Set("wait", strconv.FormatInt(wait.Milliseconds(), 10)+"ms")
+	}
+	entries, newIndex, err := client.request(ctx, prefix, query, timeout)
+	if err != nil {
+		return nil, 0, err
+	}
+	values := make(map[string]string)
+	for _, entry := range entries {
+		key := entry.Key
+		if prefix != "" {
+			// Prefix 'config/app' matches 'config/application' key too
+			if !strings.HasPrefix(key, prefix+"/") {
+				continue
+			}
+			key = key[len(prefix)+1:]
+		}
+		// Folders are skipped
+		if key == "" || strings.HasSuffix(key, "/") {
+			continue
+		}
+		values[key], err = entry.decodeValue()
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return values, newIndex, nil
+}
+
+func (client *ConsulClient) request(ctx context.Context, key string, query url.Values, timeout time.Duration) ([]consulEntry, uint64, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	reqUrl := client.address + "/v1/kv/" + (&url.URL{Path: key}).EscapedPath()
+	if len(query) > 0 {
+		reqUrl += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if client.token != "" {
+		req.Header.Set("X-Consul-Token", client.token)
+	}
+	resp, err := client.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	index, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	entries := make([]consulEntry, 0)
+	if resp.StatusCode == http.StatusNotFound {
+		return entries, index, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, &HttpStatusError{Url: reqUrl, StatusCode: resp.StatusCode}
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = json.Unmarshal(content, &entries)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, index, nil
+}
+
+func (entry consulEntry) decodeValue() (string, error) {
+	if entry.Value == nil {
+		return "", nil
+	}
+	value, err := base64.StdEncoding.DecodeString(*entry.Value)
+	return string(value), err
+}
+
+// Returns environment variable value or default value if variable is not defined.
+func getEnvDefault(key string, defaultValue string) string {
+	val, ok := os.LookupEnv(key)
+	if !ok || val == "" {
+		return defaultValue
+	}
+	return val
+}
@@ -0,0 +1,193 @@
+package dynamictags
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	CONSUL_TEST_TOKEN = "secret-token"
+)
+
+// Fake Consul key/value server.
+type fakeConsul struct {
+	mutex   sync.Mutex
+	values  map[string]*string
+	index   uint64
+	changed chan struct{}
+	// Number of received requests
+	requests int
+}
+
+func newFakeConsul(values map[string]string) *fakeConsul {
+	fake := &fakeConsul{values: make(map[string]*string), index: 1, changed: make(chan struct{})}
+	for key, val := range values {
+		encoded := base64.StdEncoding.EncodeToString([]byte(val))
+		fake.values[key] = &encoded
+	}
+	return fake
+}
+
+func (fake *fakeConsul) set(key string, value string) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	encoded := base64.StdEncoding.EncodeToString([]byte(value))
+	fake.values[key] = &encoded
+	fake.index++
+	close(fake.changed)
+	fake.changed = make(chan struct{})
+}
+
+func (fake *fakeConsul) setIndex(index uint64) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.index = index
+	close(fake.changed)
+	fake.changed = make(chan struct{})
+}
+
+func (fake *fakeConsul) requestsCount() int {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.requests
+}
+
+func (fake *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Consul-Token") != CONSUL_TEST_TOKEN {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	fake.mutex.Lock()
+	fake.requests++
+	index, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	if index != 0 && fake.index <= index {
+		changed := fake.changed
+		fake.mutex.Unlock()
+		wait, _ := time.ParseDuration(r.URL.Query().Get("wait"))
+		select {
+		case <-changed:
+		case <-time.After(wait):
+		case <-r.Context().Done():
+		}
+		fake.mutex.Lock()
+	}
+	defer fake.mutex.Unlock()
+	prefix := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	keys := make([]string, 0)
+	for key := range fake.values {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(fake.index, 10))
+	if len(keys) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	entries := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, map[string]interface{}{"Key": key, "Value": fake.values[key]})
+	}
+	json.NewEncoder(w).Encode(entries)
+}
+
+func TestConsulClientList(t *testing.T) {
+	fake := newFakeConsul(map[string]string{
+		"config/app/database/port": "5432",
+		"config/app/name":          "app",
+		"config/application/name":  "other",
+	})
+	fake.values["config/app/folder/"] = nil
+	fake.values["config/app/empty"] = nil
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := NewConsulClient(server.URL)
+	client.SetToken(CONSUL_TEST_TOKEN)
+	// Case 1 values under prefix
+	values, index, err := client.List("/config/app/")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), index)
+	assert.Equal(t, map[string]string{"database/port": "5432", "name": "app", "empty": ""}, values)
+	// Case 2 unknown prefix
+	values, _, err = client.List("config/unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+	// Case 3 access denied
+	client.SetToken("")
+	_, _, err = client.List("config/app")
+	assert.Error(t, err)
+}
+
+func TestConsulClientWatch(t *testing.T) {
+	fake := newFakeConsul(map[string]string{"config/app/name": "app"})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	t.Setenv(CONSUL_ADDRESS_ENV, strings.TrimPrefix(server.URL, "http://"))
+	t.Setenv(CONSUL_TOKEN_ENV, CONSUL_TEST_TOKEN)
+	client := NewConsulClient("")
+	_, index, err := client.List("config/app")
+	assert.NoError(t, err)
+	// Case 1 wait time is expired
+	values, newIndex, err := client.Watch(context.Background(), "config/app", index, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, index, newIndex)
+	assert.Equal(t, "app", values["name"])
+	// Case 2 value is changed
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		fake.set("config/app/name", "changed")
+	}()
+	values, newIndex, err = client.Watch(context.Background(), "config/app", index, time.Minute)
+	assert.NoError(t, err)
+	assert.Greater(t, newIndex, index)
+	assert.Equal(t, "changed", values["name"])
+	// Case 3 context is cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = client.Watch(ctx, "config/app", newIndex, time.Minute)
+	assert.Error(t, err)
+}
+
+func TestConsulClientTimeout(t *testing.T) {
+	fake := newFakeConsul(map[string]string{"config/app/name": "app"})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := NewConsulClient(server.URL)
+	client.SetToken(CONSUL_TEST_TOKEN)
+	client.SetTimeout(20 * time.Millisecond)
+	_, index, err := client.List("config/app")
+	assert.NoError(t, err)
+	// Case 1 blocking query is longer than request timeout
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		fake.set("config/app/name", "changed")
+	}()
+	values, newIndex, err := client.Watch(context.Background(), "config/app", index, time.Second)
+	assert.NoError(t, err)
+	assert.Greater(t, newIndex, index)
+	assert.Equal(t, "changed", values["name"])
+	// Case 2 wait time is expired
+	_, _, err = client.Watch(context.Background(), "config/app", newIndex, 100*time.Millisecond)
+	assert.NoError(t, err)
+	// Case 3 not blocking request timeout
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slowServer.Close()
+	client = NewConsulClient(slowServer.URL)
+	client.SetTimeout(20 * time.Millisecond)
+	_, _, err = client.Get(context.Background(), "config/app/name")
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package dynamictags
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	CONSUL_DEFAULT_RETRY_DELAY = time.Second
+)
+
+// Key/value store based on Consul key/value API. Changes are watched by
+// blocking queries.
+type ConsulKVStore struct {
+	client     *ConsulClient
+	wait       time.Duration
+	retryDelay time.Duration
+}
+
+// Create Consul key/value store.
+// Parameters:
+//   - client Consul client
+//
+// Returns:
+//   - key/value store
+func NewConsulKVStore(client *ConsulClient) *ConsulKVStore {
+	return &ConsulKVStore{client: client, wait: CONSUL_DEFAULT_WAIT, retryDelay: CONSUL_DEFAULT_RETRY_DELAY}
+}
+
+// Set blocking query wait time and delay before retry after error.
+// Parameters:
+//   - wait maximum wait time of one blocking query
+//   - retryDelay delay before next query after error
+func (store *ConsulKVStore) SetWatchTimes(wait time.Duration, retryDelay time.Duration) {
+	store.wait = wait
+	store.retryDelay = retryDelay
+}
+
+// Returns value of the key.
+// Parameters:
+//   - ctx context
+//   - key key
+//
+// Returns:
+//   - value
+//   - false if key doesn't exist
+//   - error in case of error
+func (store *ConsulKVStore) Get(ctx context.Context, key string) (string, bool, error) {
+	return store.client.Get(ctx, key)
+}
+
+// Returns all values under the prefix.
+// Parameters:
+//   - ctx context
+//   - prefix keys prefix
+//
+// Returns:
+//   - values. Keys are full keys
+//   - error in case of error
+func (store *ConsulKVStore) List(ctx context.Context, prefix string) (map[string]string, error) {
+	values, _, err := store.client.list(ctx, prefix, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]string, len(values))
+	for key, val := range values {
+		res[joinKVKey(prefix, key)] = val
+	}
+	return res, nil
+}
+
+// Watch changes of values under the prefix. Errors of blocking queries are
+// retried with retry delay.
+// Parameters:
+//   - ctx context
+//   - prefix keys prefix
+//
+// Returns:
+//   - notifications channel
+//   - error if values can't be read
+func (store *ConsulKVStore) Watch(ctx context.Context, prefix string) (<-chan struct{}, error) {
+	_, index, err := store.client.list(ctx, prefix, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	// Index 0 means non blocking query, so index is never less than 1
+	index = max(index, 1)
+	notify := make(chan struct{}, 1)
+	go func() {
+		defer close(notify)
+		for ctx.Err() == nil {
+			// Index which goes backward is reset to 0 by client
+			_, newIndex, err := store.client.Watch(ctx, prefix, index, store.wait)
+			if err != nil {
+				select {
+				case <-ctx.Done():
+				case <-time.After(store.retryDelay):
+				}
+				continue
+			}
+			newIndex = max(newIndex, 1)
+			if newIndex == index {
+				continue
+			}
+			index = newIndex
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return notify, nil
+}
@@ -0,0 +1,102 @@
+package dynamictags
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsulKVStore(t *testing.T) {
+	fake := newFakeConsul(map[string]string{"config/app/name": "app", "config/app/database/port": "5432"})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := NewConsulClient(server.URL)
+	client.SetToken(CONSUL_TEST_TOKEN)
+	store := NewConsulKVStore(client)
+	store.SetWatchTimes(time.Minute, time.Millisecond)
+	ctx := context.Background()
+	// Case 1 get
+	val, ok, err := store.Get(ctx, "config/app/name")
+	assert.Equal(t, "app", val)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	_, ok, err = store.Get(ctx, "config/app")
+	assert.False(t, ok)
+	assert.NoError(t, err)
+	// Case 2 list
+	values, err := store.List(ctx, "config/app")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"config/app/name": "app", "config/app/database/port": "5432"}, values)
+	// Case 3 watch
+	watchCtx, cancel := context.WithCancel(ctx)
+	notify, err := store.Watch(watchCtx, "config/app")
+	assert.NoError(t, err)
+	fake.set("config/app/name", "changed")
+	select {
+	case _, ok = <-notify:
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "change is not detected")
+	}
+	cancel()
+	for range notify {
+	}
+	// Case 4 converter
+	conv := NewKVStoreTagConverter(store, "config/app")
+	convVal, isSet, err := conv.GetSimpleValue("name", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, "changed", convVal)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+}
+
+func TestConsulKVStoreWatchIndex(t *testing.T) {
+	fake := newFakeConsul(map[string]string{"config/app/name": "app"})
+	fake.index = 5
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := NewConsulClient(server.URL)
+	client.SetToken(CONSUL_TEST_TOKEN)
+	store := NewConsulKVStore(client)
+	store.SetWatchTimes(time.Minute, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notify, err := store.Watch(ctx, "config/app")
+	assert.NoError(t, err)
+	// Wait for blocking query and skip previous notifications
+	waitBlocking := func() {
+		time.Sleep(50 * time.Millisecond)
+		select {
+		case <-notify:
+		default:
+		}
+	}
+	// Case 1 index goes backward
+	waitBlocking()
+	fake.setIndex(2)
+	select {
+	case <-notify:
+	case <-time.After(time.Second):
+		assert.Fail(t, "index reset is not detected")
+	}
+	waitBlocking()
+	fake.set("config/app/name", "changed")
+	select {
+	case <-notify:
+	case <-time.After(time.Second):
+		assert.Fail(t, "change after index reset is not detected")
+	}
+	// Case 2 index 0 doesn't cause non blocking queries
+	waitBlocking()
+	fake.setIndex(0)
+	waitBlocking()
+	requests := fake.requestsCount()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, requests, fake.requestsCount())
+	cancel()
+	for range notify {
+	}
+}
@@ -0,0 +1,34 @@
+package dynamictags
+
+// Create processor to process 'kv' tag.
+// This processor replace structure field with 'kv' tag
+// by value get from Consul key/value store. Example usage:
+//
+//	client := NewConsulClient("")
+//	processor, err := NewConsulProcessor(client, "config/${SERVICE}")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&serverConfiguration, nil)
+//
+// Parameters:
+//   - client Consul client
+//   - prefix keys prefix. Can contain placeholders
+//
+// Returns:
+//   - Key/value tag processor if success.
+//   - error if values can't be read
+func NewConsulProcessor(client *ConsulClient, prefix string) (*DynamicTagProcessor, error) {
+	prefix, err := ProcessString(prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+	values, _, err := client.List(prefix)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewKvTagConverter(values))
+	return &processor, nil
+}
@@ -0,0 +1,42 @@
+package dynamictags
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ConsulTestStruct struct {
+	Name     string `kv:"name"`
+	Database struct {
+		Host string `kv:"host"`
+		Port int    `kv:"port"`
+	} `kv:"database"`
+}
+
+func TestConsulProcessor(t *testing.T) {
+	fake := newFakeConsul(map[string]string{
+		"config/app/name":          "app",
+		"config/app/database/host": "db.local",
+		"config/app/database/port": "5432",
+	})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	t.Setenv("CONSUL_TEST_SERVICE", "app")
+	client := NewConsulClient(server.URL)
+	client.SetToken(CONSUL_TEST_TOKEN)
+	// Case 1 prefix with placeholder
+	processor, err := NewConsulProcessor(client, "config/${CONSUL_TEST_SERVICE}")
+	assert.NoError(t, err)
+	data := ConsulTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "app", data.Name)
+	assert.Equal(t, "db.local", data.Database.Host)
+	assert.Equal(t, 5432, data.Database.Port)
+	// Case 2 server error
+	client.SetToken("")
+	_, err = NewConsulProcessor(client, "config/app")
+	assert.Error(t, err)
+}
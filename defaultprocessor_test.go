@@ -2,6 +2,7 @@ package dynamictags
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -126,3 +127,32 @@ func TestDefaultProcessorBlackList(t *testing.T) {
 	assert.NoError(t, err)
 	verifyResult(t, testStruct, true)
 }
+
+type TestName string
+
+type TestTypedSliceStruct struct {
+	Ids      []int           `default:"1,2"`
+	Names    []TestName      `default:"a,b"`
+	Timeouts []time.Duration `default:"1s,250ms"`
+	Timeout  time.Duration   `default:"2m"`
+	Nanos    time.Duration   `default:"100"`
+}
+
+func TestDefaultProcessorTypedSlice(t *testing.T) {
+	defaultProcessor := NewDefaultProcessor()
+	// Case 1 typed slices
+	data := TestTypedSliceStruct{}
+	err := defaultProcessor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, data.Ids)
+	assert.Equal(t, []TestName{"a", "b"}, data.Names)
+	assert.Equal(t, []time.Duration{time.Second, 250 * time.Millisecond}, data.Timeouts)
+	assert.Equal(t, 2*time.Minute, data.Timeout)
+	assert.Equal(t, time.Duration(100), data.Nanos)
+	// Case 2 incorrect element
+	type IncorrectStruct struct {
+		Ids []int `default:"1,two"`
+	}
+	err = defaultProcessor.Process(&IncorrectStruct{}, nil)
+	assert.Error(t, err)
+}
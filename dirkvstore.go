@@ -0,0 +1,194 @@
+package dynamictags
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DIR_KV_DEFAULT_POLL_INTERVAL = 5 * time.Second
+)
+
+// Key/value store based on directory of files. Key is the file path
+// relative to the directory (like 'database/port' for file
+// '<dir>/database/port'), value is the file content without trailing new
+// line. Hidden files and directories are skipped, symbolic links are
+// followed (like in mounted Kubernetes ConfigMap). Changes are detected by
+// polling.
+type DirKVStore struct {
+	dir          string
+	pollInterval time.Duration
+}
+
+// Create directory key/value store.
+// Parameters:
+//   - dir directory path
+//
+// Returns:
+//   - key/value store
+func NewDirKVStore(dir string) *DirKVStore {
+	return &DirKVStore{dir: dir, pollInterval: DIR_KV_DEFAULT_POLL_INTERVAL}
+}
+
+// Set interval of changes polling.
+// Parameters:
+//   - interval polling interval
+func (store *DirKVStore) SetPollInterval(interval time.Duration) {
+	store.pollInterval = interval
+}
+
+// Returns value of the key.
+// Parameters:
+//   - ctx context
+//   - key key
+//
+// Returns:
+//   - value
+//   - false if key doesn't exist
+//   - error in case of error
+func (store *DirKVStore) Get(ctx context.Context, key string) (string, bool, error) {
+	filePath, err := store.getFilePath(key)
+	if err != nil {
+		return "", false, err
+	}
+	info, err := os.Stat(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if !info.Mode().IsRegular() {
+		return "", false, nil
+	}
+	val, err := readValueFile(filePath)
+	if err != nil {
+		return "", false, err
+	}
+	return val, true, nil
+}
+
+// Returns all values under the prefix.
+// Parameters:
+//   - ctx context
+//   - prefix keys prefix
+//
+// Returns:
+//   - values. Keys are full keys
+//   - error in case of error
+func (store *DirKVStore) List(ctx context.Context, prefix string) (map[string]string, error) {
+	res := make(map[string]string)
+	err := store.walk(prefix, func(key string, filePath string, info fs.FileInfo) error {
+		val, err := readValueFile(filePath)
+		if err != nil {
+			return err
+		}
+		res[key] = val
+		return nil
+	})
+	return res, err
+}
+
+// Watch changes of values under the prefix. Files are polled with poll
+// interval (see SetPollInterval). Change is detected by file size and
+// modification time.
+// Parameters:
+//   - ctx context
+//   - prefix keys prefix
+//
+// Returns:
+//   - notifications channel
+//   - error if directory can't be read
+func (store *DirKVStore) Watch(ctx context.Context, prefix string) (<-chan struct{}, error) {
+	state, err := store.getState(prefix)
+	if err != nil {
+		return nil, err
+	}
+	notify := make(chan struct{}, 1)
+	go func() {
+		defer close(notify)
+		ticker := time.NewTicker(store.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			newState, err := store.getState(prefix)
+			if err != nil || newState == state {
+				continue
+			}
+			state = newState
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return notify, nil
+}
+
+// Returns state of files under the prefix (names, sizes and modification times).
+func (store *DirKVStore) getState(prefix string) (string, error) {
+	var builder strings.Builder
+	err := store.walk(prefix, func(key string, filePath string, info fs.FileInfo) error {
+		builder.WriteString(key + "|" + info.ModTime().String() + "|" + strconv.FormatInt(info.Size(), 10) + "\n")
+		return nil
+	})
+	return builder.String(), err
+}
+
+func (store *DirKVStore) walk(prefix string, fn func(key string, filePath string, info fs.FileInfo) error) error {
+	prefix = strings.Trim(prefix, "/")
+	root, err := store.getFilePath(prefix)
+	if err != nil {
+		return err
+	}
+	err = filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && filePath != root {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		// Symbolic links are followed
+		info, err := os.Stat(filePath)
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		relPath, err := filepath.Rel(store.dir, filePath)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(relPath), filePath, info)
+	})
+	if errors.Is(err, fs.ErrNotExist) && prefix != "" {
+		return nil
+	}
+	return err
+}
+
+func (store *DirKVStore) getFilePath(key string) (string, error) {
+	key = strings.Trim(key, "/")
+	if key == "" {
+		return store.dir, nil
+	}
+	if !fs.ValidPath(key) || path.Clean(key) != key {
+		return "", errors.New("incorrect key '" + key + "'")
+	}
+	return filepath.Join(store.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,67 @@
+package dynamictags
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirKVStore(t *testing.T) {
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "app", "database"), 0o700)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "app", "name"), []byte("app\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "app", "database", "port"), []byte("5432"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "app", ".hidden"), []byte("hidden"), 0o600)
+	assert.NoError(t, err)
+	store := NewDirKVStore(dir)
+	ctx := context.Background()
+	// Case 1 get
+	val, ok, err := store.Get(ctx, "app/name")
+	assert.Equal(t, "app", val)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	_, ok, err = store.Get(ctx, "app/unknown")
+	assert.False(t, ok)
+	assert.NoError(t, err)
+	_, ok, err = store.Get(ctx, "app/database")
+	assert.False(t, ok)
+	assert.NoError(t, err)
+	// Case 2 key outside of directory
+	_, _, err = store.Get(ctx, "../secret")
+	assert.Error(t, err)
+	// Case 3 list
+	values, err := store.List(ctx, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app/name": "app", "app/database/port": "5432"}, values)
+	values, err = store.List(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+	// Case 4 watch
+	store.SetPollInterval(5 * time.Millisecond)
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	notify, err := store.Watch(watchCtx, "app/database")
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "app", "database", "host"), []byte("db.local"), 0o600)
+	assert.NoError(t, err)
+	select {
+	case _, ok = <-notify:
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "change is not detected")
+	}
+	// Case 5 converter
+	conv := NewKVStoreTagConverter(store, "app")
+	convVal, isSet, err := conv.GetSimpleValue("host", reflect.StructField{}, reflect.Value{}, "$.database")
+	assert.Equal(t, "db.local", convVal)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,257 @@
+package dynamictags
+
+import (
+	"os"
+	"strings"
+)
+
+// Values of '.env' file.
+type DotEnv struct {
+	values      map[string]string
+	envOverride bool
+}
+
+// Parse '.env' file content.
+// Supported syntax:
+//   - 'KEY=value' pairs. Key can be prefixed by 'export'
+//   - comments. Lines started with '#'. Comments after unquoted values should
+//     be separated by whitespace (like 'KEY=value # comment')
+//   - single quoted values. Value is used as is
+//   - double quoted values. Escapes '\n', '\r', '\t', '\"', '\\' are processed.
+//   - multi-line values in single or double quotes
+//   - ${VAR} placeholders in unquoted and double quoted values. Placeholders are
+//     processed by ProcessString rules. Variable value is get from already
+//     parsed keys or from environment variable (see envOverride)
+//
+// Parameters:
+//   - content '.env' file content
+//   - envOverride if true environment variables have priority over file values
+//
+// Returns:
+//   - parsed values
+//   - error in case of syntax error (*SyntaxError)
+func ReadDotEnv(content []byte, envOverride bool) (*DotEnv, error) {
+	env := &DotEnv{
+		values:      make(map[string]string),
+		envOverride: envOverride,
+	}
+	parser := dotEnvParser{content: string(content)}
+	for {
+		key, value, err := parser.next(env)
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			return env, nil
+		}
+		env.values[key] = value
+	}
+}
+
+// Read '.env' file.
+// Parameters:
+//   - path path to the file
+//   - envOverride if true environment variables have priority over file values
+//
+// Returns:
+//   - parsed values
+//   - error in case of read or syntax error
+func ReadDotEnvFile(path string, envOverride bool) (*DotEnv, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ReadDotEnv(content, envOverride)
+}
+
+// Returns variable value. Has the same semantic as os.LookupEnv and can be used
+// as environment lookup function (see DynamicTagProcessor.SetEnvLookup and
+// NewEnvTagConverterWithLookup).
+// Parameters:
+//   - key variable name
+//
+// Returns:
+//   - file value or environment variable value. If envOverride is set
+//     environment variable has priority
+//   - true if value exists
+func (env *DotEnv) LookupEnv(key string) (string, bool) {
+	if env.envOverride {
+		val, ok := os.LookupEnv(key)
+		if ok {
+			return val, true
+		}
+	}
+	val, ok := env.values[key]
+	if ok {
+		return val, true
+	}
+	return os.LookupEnv(key)
+}
+
+// Returns all file values.
+// Returns:
+//   - key value map
+func (env *DotEnv) Values() map[string]string {
+	return env.values
+}
+
+type dotEnvParser struct {
+	content string
+	pos     int
+}
+
+func (parser *dotEnvParser) errorf(msg string) error {
+	return newSyntaxError(parser.content, parser.pos, msg)
+}
+
+func (parser *dotEnvParser) isEnd() bool {
+	return parser.pos >= len(parser.content)
+}
+
+func (parser *dotEnvParser) skipSpaces() {
+	for !parser.isEnd() && (parser.content[parser.pos] == ' ' || parser.content[parser.pos] == '\t') {
+		parser.pos++
+	}
+}
+
+// Skip rest of the line. Only comment is allowed.
+func (parser *dotEnvParser) skipLineEnd() error {
+	parser.skipSpaces()
+	if parser.isEnd() {
+		return nil
+	}
+	ch := parser.content[parser.pos]
+	if ch != '#' && ch != '\n' && ch != '\r' {
+		return parser.errorf("unexpected character '" + string(ch) + "'")
+	}
+	parser.skipLine()
+	return nil
+}
+
+func (parser *dotEnvParser) skipLine() {
+	end := strings.IndexByte(parser.content[parser.pos:], '\n')
+	if end < 0 {
+		parser.pos = len(parser.content)
+		return
+	}
+	parser.pos += end + 1
+}
+
+// Returns next key value pair. Empty key means end of content.
+func (parser *dotEnvParser) next(env *DotEnv) (string, string, error) {
+	for {
+		parser.skipSpaces()
+		if parser.isEnd() {
+			return "", "", nil
+		}
+		ch := parser.content[parser.pos]
+		if ch == '#' || ch == '\n' || ch == '\r' {
+			parser.skipLine()
+			continue
+		}
+		break
+	}
+	if strings.HasPrefix(parser.content[parser.pos:], "export ") {
+		parser.pos += len("export ")
+		parser.skipSpaces()
+	}
+	start := parser.pos
+	for !parser.isEnd() && isDotEnvKeyChar(parser.content[parser.pos]) {
+		parser.pos++
+	}
+	key := parser.content[start:parser.pos]
+	if key == "" {
+		return "", "", parser.errorf("variable name is expected")
+	}
+	parser.skipSpaces()
+	if parser.isEnd() || parser.content[parser.pos] != '=' {
+		return "", "", parser.errorf("'=' is expected")
+	}
+	parser.pos++
+	parser.skipSpaces()
+	value, err := parser.parseValue(env)
+	if err != nil {
+		return "", "", err
+	}
+	return key, value, parser.skipLineEnd()
+}
+
+func isDotEnvKeyChar(ch byte) bool {
+	return isTomlBareKeyChar(ch) || ch == '.'
+}
+
+func (parser *dotEnvParser) parseValue(env *DotEnv) (string, error) {
+	if parser.isEnd() {
+		return "", nil
+	}
+	switch parser.content[parser.pos] {
+	case '\'':
+		start := parser.pos + 1
+		end := strings.IndexByte(parser.content[start:], '\'')
+		if end < 0 {
+			return "", parser.errorf("unterminated string")
+		}
+		parser.pos = start + end + 1
+		return parser.content[start : start+end], nil
+	case '"':
+		value, err := parser.parseDoubleQuoted()
+		if err != nil {
+			return "", err
+		}
+		return parser.interpolate(value, env)
+	}
+	start := parser.pos
+	for !parser.isEnd() && parser.content[parser.pos] != '\n' {
+		if parser.content[parser.pos] == '#' && (parser.content[parser.pos-1] == ' ' || parser.content[parser.pos-1] == '\t') {
+			break
+		}
+		parser.pos++
+	}
+	value := strings.TrimSpace(parser.content[start:parser.pos])
+	return parser.interpolate(value, env)
+}
+
+func (parser *dotEnvParser) parseDoubleQuoted() (string, error) {
+	parser.pos++
+	var builder strings.Builder
+	for {
+		if parser.isEnd() {
+			return "", parser.errorf("unterminated string")
+		}
+		ch := parser.content[parser.pos]
+		parser.pos++
+		switch ch {
+		case '"':
+			return builder.String(), nil
+		case '\\':
+			if parser.isEnd() {
+				return "", parser.errorf("unterminated string")
+			}
+			escaped := parser.content[parser.pos]
+			parser.pos++
+			switch escaped {
+			case 'n':
+				builder.WriteByte('\n')
+			case 'r':
+				builder.WriteByte('\r')
+			case 't':
+				builder.WriteByte('\t')
+			case '"', '\\':
+				builder.WriteByte(escaped)
+			default:
+				builder.WriteByte('\\')
+				builder.WriteByte(escaped)
+			}
+		default:
+			builder.WriteByte(ch)
+		}
+	}
+}
+
+func (parser *dotEnvParser) interpolate(value string, env *DotEnv) (string, error) {
+	res, err := ProcessStringWithLookup(value, nil, env.LookupEnv)
+	if err != nil {
+		return "", parser.errorf(err.Error())
+	}
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package dynamictags
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const DOTENV_DATA = `
+# comment
+export HOST=localhost
+PORT = 8080 # comment
+URL="http://${HOST}:${PORT_KEY}"
+PORT_KEY=9090
+LITERAL='${HOST} \n'
+ESCAPED="line1\nline2 \"quoted\""
+MULTILINE="first
+second"
+EMPTY=
+HASH=value#not_comment
+`
+
+func TestReadDotEnv(t *testing.T) {
+	env, err := ReadDotEnv([]byte(DOTENV_DATA), false)
+	assert.NoError(t, err)
+	values := env.Values()
+	assert.Equal(t, "localhost", values["HOST"])
+	assert.Equal(t, "8080", values["PORT"])
+	// PORT_KEY is defined after URL
+	assert.Equal(t, "http://localhost:", values["URL"])
+	assert.Equal(t, `${HOST} \n`, values["LITERAL"])
+	assert.Equal(t, "line1\nline2 \"quoted\"", values["ESCAPED"])
+	assert.Equal(t, "first\nsecond", values["MULTILINE"])
+	assert.Equal(t, "", values["EMPTY"])
+	assert.Equal(t, "value#not_comment", values["HASH"])
+}
+
+func TestDotEnvInterpolation(t *testing.T) {
+	os.Setenv("DOTENV_TEST_HOST", "env.host")
+	defer os.Unsetenv("DOTENV_TEST_HOST")
+	content := []byte("DOTENV_TEST_HOST=file.host\nURL=http://${DOTENV_TEST_HOST}/api\nPREFIX=${DOTENV_TEST_${SUFFIX}}\nSUFFIX=HOST")
+	// Case 1 file values have priority
+	env, err := ReadDotEnv(content, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://file.host/api", env.Values()["URL"])
+	val, ok := env.LookupEnv("DOTENV_TEST_HOST")
+	assert.True(t, ok)
+	assert.Equal(t, "file.host", val)
+	// Case 2 environment variables have priority
+	env, err = ReadDotEnv(content, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://env.host/api", env.Values()["URL"])
+	val, ok = env.LookupEnv("DOTENV_TEST_HOST")
+	assert.True(t, ok)
+	assert.Equal(t, "env.host", val)
+	// Case 3 unknown variable
+	_, ok = env.LookupEnv("DOTENV_TEST_UNKNOWN")
+	assert.False(t, ok)
+}
+
+func TestReadDotEnvErrors(t *testing.T) {
+	// Case 1 no '='
+	_, err := ReadDotEnv([]byte("A=1\nB"), false)
+	assert.Error(t, err)
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, syntaxErr.Line)
+	// Case 2 unterminated string
+	_, err = ReadDotEnv([]byte("A=\"value"), false)
+	assert.Error(t, err)
+	// Case 3 text after quoted value
+	_, err = ReadDotEnv([]byte("A='value' tail"), false)
+	assert.Error(t, err)
+}
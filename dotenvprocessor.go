@@ -0,0 +1,35 @@
+package dynamictags
+
+// Create processor to process 'dotenv' and 'env' tags.
+// Values of '.env' file are used as environment variables layer. I.e. they
+// are used for 'env' tags and for ${VAR} placeholders in all tags. Example usage:
+//
+//	content, err := os.ReadFile(".env")
+//	if err != nil {
+//	  return err
+//	}
+//	processor, err := NewDotEnvProcessor(content, true)
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&serverConfiguration, nil)
+//
+// Parameters:
+//   - content '.env' file content
+//   - envOverride if true environment variables have priority over file values
+//
+// Returns:
+//   - Dotenv processor if success.
+//   - error if content has syntax error
+func NewDotEnvProcessor(content []byte, envOverride bool) (*DynamicTagProcessor, error) {
+	env, err := ReadDotEnv(content, envOverride)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.SetEnvLookup(env.LookupEnv)
+	processor.AddTagConverter(NewDotEnvTagConverter(env))
+	processor.AddTagConverter(NewEnvTagConverterWithLookup(env.LookupEnv))
+	return &processor, nil
+}
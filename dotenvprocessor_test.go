@@ -0,0 +1,62 @@
+package dynamictags
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_DOTENV_TAG = "dotenv"
+	DOTENV_PROC_DATA    = `
+SERVER_NAME=TEST
+TEST_PORT=8080
+TEST_MODE=debug
+DB_PASSWORD=secret
+`
+)
+
+type DotEnvTestStruct struct {
+	Port     int    `env:"${SERVER_NAME}_PORT"`
+	Mode     string `dotenv:"${SERVER_NAME}_MODE"`
+	Password string `dotenv:"DB_PASSWORD" default:"none"`
+	User     string `dotenv:"DB_USER" default:"admin"`
+}
+
+func TestDotEnvTagConverter(t *testing.T) {
+	env, err := ReadDotEnv([]byte(DOTENV_PROC_DATA), false)
+	assert.NoError(t, err)
+	conv := NewDotEnvTagConverter(env)
+	assert.Equal(t, EXPECTED_DOTENV_TAG, conv.GetTag())
+	// Case 1 file value
+	val, isSet, err := conv.GetSimpleValue("TEST_MODE", reflect.StructField{}, reflect.Value{}, "")
+	assert.Equal(t, "debug", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 environment variable is not used for missing keys
+	os.Setenv("DOTENV_CONV_TEST", "value")
+	defer os.Unsetenv("DOTENV_CONV_TEST")
+	_, isSet, err = conv.GetSimpleValue("DOTENV_CONV_TEST", reflect.StructField{}, reflect.Value{}, "")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+}
+
+func TestDotEnvConversion(t *testing.T) {
+	processor, err := NewDotEnvProcessor([]byte(DOTENV_PROC_DATA), false)
+	assert.NoError(t, err)
+	processor.AddTagConverter(NewDefaultTagConverter())
+	testStruct := DotEnvTestStruct{}
+	err = processor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, testStruct.Port)
+	assert.Equal(t, "debug", testStruct.Mode)
+	assert.Equal(t, "secret", testStruct.Password)
+	assert.Equal(t, "admin", testStruct.User)
+
+	// Syntax error
+	processor, err = NewDotEnvProcessor([]byte("A"), false)
+	assert.Error(t, err)
+	assert.Nil(t, processor)
+}
@@ -0,0 +1,59 @@
+package dynamictags
+
+import "reflect"
+
+const (
+	DOTENV_TAG = "dotenv"
+)
+
+type DotEnvTagConverter struct {
+	env *DotEnv
+}
+
+// Set structure field with 'dotenv' tag to value of variable defined in
+// '.env' file. Tag value is variable name. If environment variables override
+// file values (see ReadDotEnv) and the environment variable is defined,
+// environment variable value is used.
+// Parameters:
+//   - env parsed '.env' file
+//
+// Returns:
+//   - Dotenv tag converter.
+func NewDotEnvTagConverter(env *DotEnv) TagConverterer {
+	return &DotEnvTagConverter{env: env}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *DotEnvTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	_, ok := conv.env.values[tag]
+	if !ok {
+		return "", false, nil
+	}
+	val, ok := conv.env.LookupEnv(tag)
+	return val, ok, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv DotEnvTagConverter) GetTag() string {
+	return DOTENV_TAG
+}
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv DotEnvTagConverter) HasEncryptedValues() bool {
+	return true
+}
@@ -0,0 +1,63 @@
+package dynamictags
+
+// Create processor to process 'dsn' and 'default' tags. Example usage:
+//
+//	processor, err := NewDsnProcessor("${DATABASE_URL}")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&databaseConfiguration, nil)
+//
+// Parameters:
+//   - dsn connection string. Can contain placeholders (for example
+//     '${DATABASE_URL}' means connection string from environment variable)
+//
+// Returns:
+//   - Connection string tag processor if success.
+//   - error if connection string can't be parsed
+func NewDsnProcessor(dsn string) (*DynamicTagProcessor, error) {
+	dsn, err := ProcessString(dsn, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newDsnProcessor(dsn)
+}
+
+// Create processor to process 'dsn' and 'default' tags. Connection string
+// is got from configuration tree. Example usage:
+//
+//	tree, err := ReadTreeFile("config.json", nil)
+//	...
+//	processor, err := NewDsnTreeProcessor(tree, "$.database.url")
+//
+// Parameters:
+//   - tree configuration tree (for example parsed json document)
+//   - dsnPath json path to connection string. Connection string can contain
+//     placeholders
+//
+// Returns:
+//   - Connection string tag processor if success.
+//   - error if connection string is not found or can't be parsed
+func NewDsnTreeProcessor(tree any, dsnPath string) (*DynamicTagProcessor, error) {
+	dsn, err := getTreeDsn(tree, dsnPath)
+	if err != nil {
+		return nil, err
+	}
+	dsn, err = ProcessString(dsn, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newDsnProcessor(dsn)
+}
+
+func newDsnProcessor(dsn string) (*DynamicTagProcessor, error) {
+	converter, err := NewDsnTagConverter(dsn)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(converter)
+	processor.AddTagConverter(NewDefaultTagConverter())
+	return &processor, nil
+}
@@ -1,17 +1,28 @@
 package dynamictags
 
 import (
+	"encoding/json"
 	"errors"
+	"os"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
 // Base struct for dynamic tag processors.
 type DynamicTagProcessor struct {
 	dictionary map[string]string
 	converters []TagConverterer
+	envLookup  EnvLookupFunc
+	profile    string
+	// Key of encrypted values
+	decryptionKey []byte
 }
 
 // Init dynamic processor
@@ -48,6 +59,62 @@ func (processor *DynamicTagProcessor) RemoveDictionaryValue(key string) {
 	delete(processor.dictionary, key)
 }
 
+// Set environment variables lookup function. This function is used to
+// get environment variables values during tags processing. For example
+// DotEnv.LookupEnv allows to use values from '.env' file.
+// Parameters:
+//   - lookup lookup function. If nil os.LookupEnv is used
+func (processor *DynamicTagProcessor) SetEnvLookup(lookup EnvLookupFunc) {
+	processor.envLookup = lookup
+}
+
+// Set profile. Profile activates profile specific tags and overlay of
+// configuration documents (see ApplyProfile). For example for 'prod'
+// profile tag 'default.prod' is used instead of 'default' tag if field has
+// it:
+//
+//	type Data struct {
+//	  Host string `default:"localhost" default.prod:"db.prod"`
+//	}
+//
+// If profile is not set it is get from the dictionary 'APP_PROFILE' key or
+// from 'APP_PROFILE' environment variable (see ResolveProfile).
+// Parameters:
+//   - profile profile name
+func (processor *DynamicTagProcessor) SetProfile(profile string) {
+	processor.profile = profile
+}
+
+// Returns profile used for processing.
+// Returns:
+//   - profile set by SetProfile or profile from dictionary or environment
+//     variable. Empty string if profile is not selected
+func (processor DynamicTagProcessor) GetProfile() string {
+	if processor.profile != "" {
+		return processor.profile
+	}
+	lookup := processor.envLookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	return resolveProfile(processor.dictionary, lookup)
+}
+
+// Set key of encrypted values. Values like 'ENC[AES256_GCM,data:...,iv:...]'
+// got from configuration converters (json, yaml, toml, ini, properties, .env,
+// xml, environment variables, files and key/value stores) are decrypted
+// before assignment (see EncryptValue and EncryptedValuesSource). Values of
+// other converters (like http request or command line flags) are not
+// decrypted.
+// If key is not set it is get from 'DYNAMICTAGS_KEY' environment variable
+// or from file specified by 'DYNAMICTAGS_KEY_FILE' environment variable
+// when encrypted value is found.
+// Parameters:
+//   - key 32 bytes key
+func (processor *DynamicTagProcessor) SetDecryptionKey(key []byte) {
+	processor.decryptionKey = key
+}
+
 // Add tag converter
 // Parameters:
 //   - converter tag converter.
@@ -87,11 +154,52 @@ func (processor DynamicTagProcessor) Process(data any, blackList []string) error
 	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
 		return errors.New("pointer to structure is expected")
 	}
+	processor.profile = processor.GetProfile()
+	converters := make([]TagConverterer, 0, len(processor.converters))
+	for _, converter := range processor.converters {
+		profileConverter, ok := converter.(ProfileConverter)
+		if ok {
+			var err error
+			converter, err = profileConverter.WithProfile(processor.profile)
+			if err != nil {
+				return err
+			}
+		}
+		converters = append(converters, converter)
+	}
+	for i, converter := range converters {
+		listener, ok := converter.(ProcessListener)
+		if ok {
+			processConverter := listener.BeginProcess()
+			defer listener.EndProcess(processConverter)
+			converters[i] = processConverter
+		}
+	}
+	processor.converters = converters
 	tagpaths := make(map[string]string)
 	err := processor.processStructure(t, v, "$", tagpaths, blackList)
 	return err
 }
 
+func (processor DynamicTagProcessor) processString(str string) (string, error) {
+	lookup := processor.envLookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	return ProcessStringWithLookup(str, processor.dictionary, lookup)
+}
+
+// Returns tag value. Profile specific tag (like 'default.prod') has priority.
+func (processor DynamicTagProcessor) getTagValue(t reflect.StructField, tag string) string {
+	if processor.profile != "" {
+		tagVal, ok := t.Tag.Lookup(tag + "." + processor.profile)
+		if ok {
+			return tagVal
+		}
+	}
+	return t.Tag.Get(tag)
+}
+
 func (processor DynamicTagProcessor) convertBool(val any) (bool, error) {
 	res, ok := val.(bool)
 	if ok {
@@ -208,21 +316,13 @@ func (processor DynamicTagProcessor) getFloatInterfaceValue(val any) (float64, e
 	return 0, err
 }
 
-func (processor DynamicTagProcessor) convertSliceInterface(src []interface{}) ([]string, error) {
-	res := make([]string, 0, len(src))
-	for _, srcData := range src {
-		str, ok := srcData.(string)
-		if !ok {
-			return res, errors.New("incompatible slice elements type")
-		}
-		res = append(res, str)
+func (processor DynamicTagProcessor) convertSliceString(src string) []interface{} {
+	items := strings.Split(src, ",")
+	res := make([]interface{}, len(items))
+	for i, item := range items {
+		res[i] = item
 	}
-	return res, nil
-}
-
-func (processor DynamicTagProcessor) convertSliceString(src string) ([]string, error) {
-	res := strings.Split(src, ",")
-	return res, nil
+	return res
 }
 
 func (processor DynamicTagProcessor) setInterfaceSimpleValue(t reflect.StructField, v reflect.Value, val any, path string) error {
@@ -251,16 +351,86 @@ func (processor DynamicTagProcessor) setInterfaceSimpleValue(t reflect.StructFie
 			return err
 		}
 		v.SetBool(valBool)
+	case reflect.Map, reflect.Interface:
+		return processor.setTreeValue(v, val)
 	case reflect.Slice:
+		if v.Type() == rawMessageType {
+			return processor.setTreeValue(v, val)
+		}
 		slice, ok := val.([]interface{})
 		if ok {
-			sliceVal, err := processor.convertSliceInterface(slice)
-			if err != nil {
-				return err
-			}
-			v.Set(reflect.ValueOf(sliceVal))
+			return processor.setSliceValue(t, v, slice, path)
+		}
+	case reflect.Struct:
+		if v.Type() != timeType {
+			return errors.New("unsupported structure type. Path: " + path)
+		}
+		valTime, ok := val.(time.Time)
+		if !ok {
+			return errors.New("time value is expected. Path: " + path)
+		}
+		v.Set(reflect.ValueOf(valTime))
+	}
+	return nil
+}
+
+// Set slice elements. Elements can have any simple type.
+func (processor DynamicTagProcessor) setSliceValue(t reflect.StructField, v reflect.Value, val []interface{}, path string) error {
+	slice := reflect.MakeSlice(v.Type(), len(val), len(val))
+	elemField := reflect.StructField{Name: t.Name, Type: v.Type().Elem()}
+	for i, item := range val {
+		var err error
+		strVal, ok := item.(string)
+		if ok {
+			err = processor.setStringSimpleValue(elemField, slice.Index(i), strVal, path)
+		} else if elemField.Type.Kind() == reflect.String {
+			err = errors.New("incompatible slice elements type. Path: " + path + "." + t.Name)
+		} else {
+			err = processor.setInterfaceSimpleValue(elemField, slice.Index(i), item, path)
+		}
+		if err != nil {
+			return err
 		}
 	}
+	v.Set(slice)
+	return nil
+}
+
+// Set configuration subtree (result of json unmarshaling) to the field.
+// Field can be json.RawMessage, interface or any type compatible with
+// the subtree (for example map[string]any)
+func (processor DynamicTagProcessor) setTreeValue(v reflect.Value, val any) error {
+	if v.Type() == rawMessageType {
+		data, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		v.SetBytes(data)
+		return nil
+	}
+	if val == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	treeVal := reflect.ValueOf(val)
+	if treeVal.Type().AssignableTo(v.Type()) {
+		v.Set(treeVal)
+		return nil
+	}
+	if v.Kind() == reflect.Interface {
+		return errors.New("value of type " + treeVal.Type().String() + " can't be assigned to " + v.Type().String())
+	}
+	// Types are different (for example map[string]int). Convert via json
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	res := reflect.New(v.Type())
+	err = json.Unmarshal(data, res.Interface())
+	if err != nil {
+		return err
+	}
+	v.Set(res.Elem())
 	return nil
 }
 
@@ -269,6 +439,14 @@ func (processor DynamicTagProcessor) setStringSimpleValue(t reflect.StructField,
 	case reflect.String:
 		v.SetString(val)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Duration can be set as duration string (like '10s') or as number of nanoseconds
+		if t.Type == durationType {
+			d, err := time.ParseDuration(val)
+			if err == nil {
+				v.SetInt(int64(d))
+				return nil
+			}
+		}
 		n, err := strconv.ParseInt(val, 10, 64)
 		if err != nil {
 			return err
@@ -301,56 +479,244 @@ func (processor DynamicTagProcessor) setStringSimpleValue(t reflect.StructField,
 			return err
 		}
 		v.SetBool(n)
+	case reflect.Interface:
+		if !reflect.TypeOf(val).AssignableTo(v.Type()) {
+			return errors.New("value can't be assigned to " + v.Type().String() + ". Path: " + path + "." + t.Name)
+		}
+		v.Set(reflect.ValueOf(val))
+	case reflect.Map:
+		// Map value is expected in json format
+		var tree any
+		err := json.Unmarshal([]byte(val), &tree)
+		if err != nil {
+			return err
+		}
+		return processor.setTreeValue(v, tree)
 	case reflect.Slice:
-		sliceVal, err := processor.convertSliceString(val)
+		if v.Type() == rawMessageType {
+			return processor.setTreeValue(v, val)
+		}
+		// Slice value is comma separated list
+		return processor.setSliceValue(t, v, processor.convertSliceString(val), path)
+	case reflect.Struct:
+		if t.Type != timeType {
+			return errors.New("unexpected key type")
+		}
+		valTime, err := time.Parse(time.RFC3339Nano, val)
 		if err != nil {
 			return err
 		}
-		v.Set(reflect.ValueOf(sliceVal))
+		v.Set(reflect.ValueOf(valTime))
 	default:
 		return errors.New("unexpected key type")
 	}
 	return nil
 }
 
-func (processor DynamicTagProcessor) processSimpleType(t reflect.StructField, v reflect.Value, tagpaths map[string]string, path string) error {
+// Returns value from the first converter which can provide the value.
+// Returns:
+//   - value
+//   - converter which provides the value
+//   - true if value is found
+//   - error in case of error
+func (processor DynamicTagProcessor) getConverterValue(t reflect.StructField, v reflect.Value, tagpaths map[string]string, path string) (any, TagConverterer, bool, error) {
 	for _, converter := range processor.converters {
 		tag := converter.GetTag()
-		tagVal := t.Tag.Get(tag)
+		tagVal := processor.getTagValue(t, tag)
 		if tagVal == "" {
 			continue
 		}
-		res, err := ProcessString(tagVal, processor.dictionary)
-		if err != nil {
-			return err
-		}
 		tagPath, ok := tagpaths[tag]
 		if !ok {
 			tagPath = path
 		}
-		val, isSet, err := converter.GetSimpleValue(res, t, v, tagPath)
+		var val any
+		var isSet bool
+		var err error
+		rawConverter, ok := converter.(RawTagConverter)
+		if ok {
+			val, isSet, err = rawConverter.GetRawValue(tagVal, processor.processString, t, v, tagPath)
+		} else {
+			var res string
+			res, err = processor.processString(tagVal)
+			if err != nil {
+				return nil, nil, false, err
+			}
+			val, isSet, err = converter.GetSimpleValue(res, t, v, tagPath)
+		}
 		if err != nil {
-			return err
+			return nil, nil, false, err
 		}
 		if isSet {
-			strVal, ok := val.(string)
+			return val, converter, true, nil
+		}
+	}
+	return nil, nil, false, nil
+}
+
+func (processor DynamicTagProcessor) processSimpleType(t reflect.StructField, v reflect.Value, tagpaths map[string]string, path string) error {
+	val, converter, isSet, err := processor.getConverterValue(t, v, tagpaths, path)
+	if err != nil || !isSet {
+		return err
+	}
+	if processor.hasEncryptedValues(converter) {
+		val, err = processor.decryptTree(val)
+		if err != nil {
+			return errors.New(err.Error() + ". Path: " + path + "." + t.Name)
+		}
+	}
+	strVal, ok := val.(string)
+	if ok {
+		return processor.setStringSimpleValue(t, v, strVal, path)
+	}
+	return processor.setInterfaceSimpleValue(t, v, val, path)
+}
+
+// Decrypt encrypted strings of the value. Elements of slices and values of
+// maps are decrypted too. Source slices and maps are not modified.
+func (processor DynamicTagProcessor) decryptTree(val any) (any, error) {
+	var err error
+	switch typedVal := val.(type) {
+	case string:
+		if IsEncryptedValue(typedVal) {
+			return processor.decryptValue(typedVal)
+		}
+	case []interface{}:
+		res := make([]interface{}, len(typedVal))
+		for i, item := range typedVal {
+			res[i], err = processor.decryptTree(item)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(typedVal))
+		for key, item := range typedVal {
+			res[key], err = processor.decryptTree(item)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+	return val, nil
+}
+
+func (processor DynamicTagProcessor) hasEncryptedValues(converter TagConverterer) bool {
+	source, ok := converter.(EncryptedValuesSource)
+	return ok && source.HasEncryptedValues()
+}
+
+func (processor DynamicTagProcessor) decryptValue(val string) (string, error) {
+	key := processor.decryptionKey
+	if key == nil {
+		var err error
+		key, err = LoadEncryptionKey()
+		if err != nil {
+			return "", err
+		}
+	}
+	return DecryptValue(val, key)
+}
+
+// Process slice of structures. Each element of the slice is processed as
+// nested structure. Path of element is the slice path with index (like '$.Servers[1]').
+// String value (for example from 'default' tag) is expected in json format.
+// Field without tags is looked up by field name (like nested structure).
+func (processor DynamicTagProcessor) processStructSlice(t reflect.StructField, v reflect.Value, path string, tagpaths map[string]string, blackList []string) error {
+	val, _, isSet, err := processor.getConverterValue(t, v, tagpaths, path)
+	if err != nil {
+		return err
+	}
+	if !isSet && !processor.hasTags(t) {
+		val, isSet = processor.getUntaggedSliceValue(t, v, tagpaths, path)
+	}
+	if !isSet {
+		return nil
+	}
+	strVal, ok := val.(string)
+	if ok {
+		var tree any
+		err := json.Unmarshal([]byte(strVal), &tree)
+		if err != nil {
+			return err
+		}
+		return processor.setTreeValue(v, tree)
+	}
+	items, ok := val.([]interface{})
+	if !ok {
+		return errors.New("slice value is expected. Path: " + path + "." + t.Name)
+	}
+	newTagsPath, err := processor.fillTagsPath(t, tagpaths)
+	if err != nil {
+		return err
+	}
+	slice := reflect.MakeSlice(t.Type, len(items), len(items))
+	composers := make(map[string]ItemPathComposer)
+	for _, converter := range processor.converters {
+		composer, ok := converter.(ItemPathComposer)
+		if ok {
+			composers[converter.GetTag()] = composer
+		}
+	}
+	for i := range items {
+		index := "[" + strconv.Itoa(i) + "]"
+		itemTagsPath := make(map[string]string, len(newTagsPath))
+		for tag, tagPath := range newTagsPath {
+			composer, ok := composers[tag]
 			if ok {
-				err = processor.setStringSimpleValue(t, v, strVal, path)
+				itemTagsPath[tag] = composer.ComposeItemPath(tagPath, i)
 			} else {
-				err = processor.setInterfaceSimpleValue(t, v, val, path)
+				itemTagsPath[tag] = tagPath + index
 			}
+		}
+		err = processor.processStructure(t.Type.Elem(), slice.Index(i), path+"."+t.Name+index, itemTagsPath, blackList)
+		if err != nil {
 			return err
 		}
 	}
+	v.Set(slice)
 	return nil
 }
 
+// Returns true if field has tag of any converter.
+func (processor DynamicTagProcessor) hasTags(t reflect.StructField) bool {
+	for _, converter := range processor.converters {
+		if processor.getTagValue(t, converter.GetTag()) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns slice value of field without tags. Field name is used as tag.
+// Only slice values are used. Errors are ignored because value of field
+// without tags is optional.
+func (processor DynamicTagProcessor) getUntaggedSliceValue(t reflect.StructField, v reflect.Value, tagpaths map[string]string, path string) (any, bool) {
+	for _, converter := range processor.converters {
+		tagPath, ok := tagpaths[converter.GetTag()]
+		if !ok {
+			tagPath = path
+		}
+		val, isSet, err := converter.GetSimpleValue(t.Name, t, v, tagPath)
+		if err != nil || !isSet {
+			continue
+		}
+		_, ok = val.([]interface{})
+		if ok {
+			return val, true
+		}
+	}
+	return nil, false
+}
+
 func (processor DynamicTagProcessor) fillTagsPath(t reflect.StructField, tagPaths map[string]string) (map[string]string, error) {
 	newMap := make(map[string]string, len(tagPaths))
 	for _, converter := range processor.converters {
 		tag := converter.GetTag()
-		tagVal := t.Tag.Get(tag)
-		tagVal, err := ProcessString(tagVal, processor.dictionary)
+		tagVal := processor.getTagValue(t, tag)
+		tagVal, err := processor.processString(tagVal)
 		if err != nil {
 			return newMap, err
 		}
@@ -364,13 +730,26 @@ func (processor DynamicTagProcessor) fillTagsPath(t reflect.StructField, tagPath
 			if !ok {
 				currTagPath = "$"
 			}
-			currTagPath = currTagPath + "." + tagVal
+			composer, ok := converter.(TagPathComposer)
+			if ok {
+				currTagPath = composer.ComposeTagPath(currTagPath, tagVal)
+			} else {
+				currTagPath = currTagPath + "." + tagVal
+			}
 		}
 		newMap[tag] = currTagPath
 	}
 	return newMap, nil
 }
 
+func (processor DynamicTagProcessor) isStructSlice(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	elemType := v.Type().Elem()
+	return elemType.Kind() == reflect.Struct && elemType != timeType
+}
+
 func (processor DynamicTagProcessor) processStructure(t reflect.Type, v reflect.Value, path string, tagpaths map[string]string, blackList []string) error {
 	var structValue reflect.Value
 	var structType reflect.Type
@@ -390,7 +769,9 @@ func (processor DynamicTagProcessor) processStructure(t reflect.Type, v reflect.
 		var err error = nil
 		currPath := path + "." + fieldType.Name
 		if blackList == nil || !slices.Contains(blackList, currPath) {
-			if fieldValue.Kind() == reflect.Struct {
+			if processor.isStructSlice(fieldValue) {
+				err = processor.processStructSlice(fieldType, fieldValue, path, tagpaths, blackList)
+			} else if fieldValue.Kind() == reflect.Struct && fieldValue.Type() != timeType {
 				newTagsPath, err := processor.fillTagsPath(fieldType, tagpaths)
 				if err != nil {
 					return err
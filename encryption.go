@@ -0,0 +1,178 @@
+package dynamictags
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+)
+
+const (
+	ENCRYPTED_VALUE_PREFIX  = "ENC["
+	ENCRYPTED_VALUE_SUFFIX  = "]"
+	ENCRYPTION_AES256_GCM   = "AES256_GCM"
+	ENCRYPTION_KEY_ENV      = "DYNAMICTAGS_KEY"
+	ENCRYPTION_KEY_FILE_ENV = "DYNAMICTAGS_KEY_FILE"
+	ENCRYPTION_KEY_SIZE     = 32
+)
+
+// Generate random encryption key.
+// Returns:
+//   - base64 encoded key. Can be stored in key file or 'DYNAMICTAGS_KEY'
+//     environment variable
+//   - error in case of error
+func GenerateEncryptionKey() (string, error) {
+	key := make([]byte, ENCRYPTION_KEY_SIZE)
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Parse base64 encoded encryption key.
+// Parameters:
+//   - encodedKey base64 encoded 32 bytes key
+//
+// Returns:
+//   - key
+//   - error if key is incorrect
+func ParseEncryptionKey(encodedKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, errors.New("encryption key should be base64 encoded")
+	}
+	if len(key) != ENCRYPTION_KEY_SIZE {
+		return nil, errors.New("encryption key should have 32 bytes")
+	}
+	return key, nil
+}
+
+// Read encryption key from file.
+// Parameters:
+//   - filePath path to the file with base64 encoded key
+//
+// Returns:
+//   - key
+//   - error if file can't be read or key is incorrect
+func ReadEncryptionKeyFile(filePath string) ([]byte, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEncryptionKey(string(content))
+}
+
+// Returns encryption key from 'DYNAMICTAGS_KEY' environment variable or
+// from file specified by 'DYNAMICTAGS_KEY_FILE' environment variable.
+// Returns:
+//   - key
+//   - error if key is not defined or incorrect
+func LoadEncryptionKey() ([]byte, error) {
+	encodedKey, ok := os.LookupEnv(ENCRYPTION_KEY_ENV)
+	if ok && encodedKey != "" {
+		return ParseEncryptionKey(encodedKey)
+	}
+	keyFile, ok := os.LookupEnv(ENCRYPTION_KEY_FILE_ENV)
+	if ok && keyFile != "" {
+		return ReadEncryptionKeyFile(keyFile)
+	}
+	return nil, errors.New("encryption key is not defined")
+}
+
+// Returns true if value is encrypted (has format 'ENC[...]').
+// Parameters:
+//   - value value
+//
+// Returns:
+//   - true if value is encrypted
+func IsEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, ENCRYPTED_VALUE_PREFIX) && strings.HasSuffix(value, ENCRYPTED_VALUE_SUFFIX)
+}
+
+// Encrypt value with AES-256 GCM. Result has format
+// 'ENC[AES256_GCM,data:<base64>,iv:<base64>]' and can be stored in
+// configuration file or environment variable.
+// Parameters:
+//   - value value to encrypt
+//   - key 32 bytes key
+//
+// Returns:
+//   - encrypted value
+//   - error in case of error
+func EncryptValue(value string, key []byte) (string, error) {
+	aead, err := newEncryptionAead(key)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, aead.NonceSize())
+	_, err = rand.Read(iv)
+	if err != nil {
+		return "", err
+	}
+	data := aead.Seal(nil, iv, []byte(value), nil)
+	return ENCRYPTED_VALUE_PREFIX + ENCRYPTION_AES256_GCM +
+		",data:" + base64.StdEncoding.EncodeToString(data) +
+		",iv:" + base64.StdEncoding.EncodeToString(iv) + ENCRYPTED_VALUE_SUFFIX, nil
+}
+
+// Decrypt value encrypted by EncryptValue.
+// Parameters:
+//   - value encrypted value
+//   - key 32 bytes key
+//
+// Returns:
+//   - decrypted value
+//   - error if value has incorrect format or can't be decrypted with the key
+func DecryptValue(value string, key []byte) (string, error) {
+	if !IsEncryptedValue(value) {
+		return "", errors.New("encrypted value should have format 'ENC[...]'")
+	}
+	parts := strings.Split(value[len(ENCRYPTED_VALUE_PREFIX):len(value)-len(ENCRYPTED_VALUE_SUFFIX)], ",")
+	if parts[0] != ENCRYPTION_AES256_GCM {
+		return "", errors.New("unsupported encryption '" + parts[0] + "'")
+	}
+	fields := make(map[string][]byte)
+	for _, part := range parts[1:] {
+		name, encoded, ok := strings.Cut(part, ":")
+		if !ok {
+			return "", errors.New("incorrect encrypted value field '" + part + "'")
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", errors.New("incorrect encrypted value field '" + name + "'")
+		}
+		fields[name] = decoded
+	}
+	data, hasData := fields["data"]
+	iv, hasIv := fields["iv"]
+	if !hasData || !hasIv {
+		return "", errors.New("encrypted value should have 'data' and 'iv' fields")
+	}
+	aead, err := newEncryptionAead(key)
+	if err != nil {
+		return "", err
+	}
+	if len(iv) != aead.NonceSize() {
+		return "", errors.New("incorrect encrypted value 'iv' field")
+	}
+	res, err := aead.Open(nil, iv, data, nil)
+	if err != nil {
+		return "", errors.New("value can't be decrypted")
+	}
+	return string(res), nil
+}
+
+func newEncryptionAead(key []byte) (cipher.AEAD, error) {
+	if len(key) != ENCRYPTION_KEY_SIZE {
+		return nil, errors.New("encryption key should have 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,172 @@
+package dynamictags
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	TEST_ENCRYPTION_KEY = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	TEST_PLAIN_VALUE    = "db password"
+)
+
+type EncryptionTestStruct struct {
+	Host     string `json:"host"`
+	Password string `json:"password"`
+	Token    string `env:"ENCRYPTION_TEST_TOKEN"`
+}
+
+type EncryptionCollectionsTestStruct struct {
+	Passwords []string          `json:"passwords"`
+	Tokens    map[string]string `json:"tokens"`
+	EnvTokens []string          `env:"ENCRYPTION_TEST_TOKENS"`
+}
+
+type EncryptionPassThroughTestStruct struct {
+	Value   string `test:"value"`
+	Default string `default:"ENC[AES256_GCM,data:AAAA,iv:AAAA]"`
+}
+
+func TestEncryptValue(t *testing.T) {
+	key, err := ParseEncryptionKey(TEST_ENCRYPTION_KEY)
+	assert.NoError(t, err)
+	// Case 1 encrypt and decrypt
+	encrypted, err := EncryptValue(TEST_PLAIN_VALUE, key)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "ENC[AES256_GCM,data:"))
+	assert.True(t, IsEncryptedValue(encrypted))
+	decrypted, err := DecryptValue(encrypted, key)
+	assert.NoError(t, err)
+	assert.Equal(t, TEST_PLAIN_VALUE, decrypted)
+	// Case 2 random iv
+	other, err := EncryptValue(TEST_PLAIN_VALUE, key)
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, other)
+	// Case 3 incorrect key
+	otherKey, err := GenerateEncryptionKey()
+	assert.NoError(t, err)
+	key2, err := ParseEncryptionKey(otherKey)
+	assert.NoError(t, err)
+	_, err = DecryptValue(encrypted, key2)
+	assert.Error(t, err)
+	// Case 4 incorrect values
+	assert.False(t, IsEncryptedValue(TEST_PLAIN_VALUE))
+	_, err = DecryptValue(TEST_PLAIN_VALUE, key)
+	assert.Error(t, err)
+	_, err = DecryptValue("ENC[AES128_CBC,data:AAAA,iv:AAAA]", key)
+	assert.Error(t, err)
+	_, err = DecryptValue("ENC[AES256_GCM,data:AAAA]", key)
+	assert.Error(t, err)
+	_, err = DecryptValue("ENC[AES256_GCM,data:!!!,iv:AAAA]", key)
+	assert.Error(t, err)
+	_, err = DecryptValue("ENC[AES256_GCM,data:AAAA,iv:AAAA]", key)
+	assert.Error(t, err)
+	// Case 5 incorrect keys
+	_, err = ParseEncryptionKey("not a key")
+	assert.Error(t, err)
+	_, err = ParseEncryptionKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+	_, err = EncryptValue(TEST_PLAIN_VALUE, []byte("short"))
+	assert.Error(t, err)
+}
+
+func TestLoadEncryptionKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "config.key")
+	err := os.WriteFile(keyFile, []byte(TEST_ENCRYPTION_KEY+"\n"), 0o600)
+	assert.NoError(t, err)
+	expected, err := ParseEncryptionKey(TEST_ENCRYPTION_KEY)
+	assert.NoError(t, err)
+	// Case 1 key is not defined
+	t.Setenv(ENCRYPTION_KEY_ENV, "")
+	t.Setenv(ENCRYPTION_KEY_FILE_ENV, "")
+	_, err = LoadEncryptionKey()
+	assert.Error(t, err)
+	// Case 2 key file
+	t.Setenv(ENCRYPTION_KEY_FILE_ENV, keyFile)
+	key, err := LoadEncryptionKey()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, key)
+	// Case 3 environment variable has priority
+	t.Setenv(ENCRYPTION_KEY_ENV, "incorrect")
+	_, err = LoadEncryptionKey()
+	assert.Error(t, err)
+}
+
+func TestProcessorDecryption(t *testing.T) {
+	key, err := ParseEncryptionKey(TEST_ENCRYPTION_KEY)
+	assert.NoError(t, err)
+	encrypted, err := EncryptValue(TEST_PLAIN_VALUE, key)
+	assert.NoError(t, err)
+	encryptedToken, err := EncryptValue("token", key)
+	assert.NoError(t, err)
+	t.Setenv("ENCRYPTION_TEST_TOKEN", encryptedToken)
+	tree := map[string]interface{}{"host": "localhost", "password": encrypted}
+	processor, err := NewJsonProcessor(tree, "$")
+	assert.NoError(t, err)
+	processor.AddTagConverter(NewEnvTagConverter())
+	// Case 1 key from environment variable
+	t.Setenv(ENCRYPTION_KEY_ENV, TEST_ENCRYPTION_KEY)
+	data := EncryptionTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, EncryptionTestStruct{Host: "localhost", Password: TEST_PLAIN_VALUE, Token: "token"}, data)
+	// Case 2 key is not defined
+	t.Setenv(ENCRYPTION_KEY_ENV, "")
+	t.Setenv(ENCRYPTION_KEY_FILE_ENV, "")
+	err = processor.Process(&data, nil)
+	assert.Error(t, err)
+	// Case 3 key is set to processor
+	processor.SetDecryptionKey(key)
+	data = EncryptionTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, TEST_PLAIN_VALUE, data.Password)
+	// Case 4 slice elements and map values
+	tree = map[string]interface{}{
+		"passwords": []interface{}{encrypted, "plain"},
+		"tokens":    map[string]interface{}{"api": encryptedToken},
+	}
+	processor, err = NewJsonProcessor(tree, "$")
+	assert.NoError(t, err)
+	processor.AddTagConverter(NewEnvTagConverter())
+	processor.SetDecryptionKey(key)
+	encryptedList, err := EncryptValue("a,b", key)
+	assert.NoError(t, err)
+	t.Setenv("ENCRYPTION_TEST_TOKENS", encryptedList)
+	collections := EncryptionCollectionsTestStruct{}
+	err = processor.Process(&collections, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{TEST_PLAIN_VALUE, "plain"}, collections.Passwords)
+	assert.Equal(t, map[string]string{"api": "token"}, collections.Tokens)
+	assert.Equal(t, []string{"a", "b"}, collections.EnvTokens)
+	assert.Equal(t, []interface{}{encrypted, "plain"}, tree["passwords"])
+}
+
+func TestProcessorDecryptionOptIn(t *testing.T) {
+	key, err := ParseEncryptionKey(TEST_ENCRYPTION_KEY)
+	assert.NoError(t, err)
+	encrypted, err := EncryptValue(TEST_PLAIN_VALUE, key)
+	assert.NoError(t, err)
+	processor := NewMapProcessor("test", map[string]any{"value": encrypted})
+	processor.AddTagConverter(NewDefaultTagConverter())
+	processor.SetDecryptionKey(key)
+	// Case 1 values of not configuration converters are not decrypted
+	data := EncryptionPassThroughTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, encrypted, data.Value)
+	assert.Equal(t, "ENC[AES256_GCM,data:AAAA,iv:AAAA]", data.Default)
+	// Case 2 key is not required
+	processor.SetDecryptionKey(nil)
+	t.Setenv(ENCRYPTION_KEY_ENV, "")
+	t.Setenv(ENCRYPTION_KEY_FILE_ENV, "")
+	data = EncryptionPassThroughTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, encrypted, data.Value)
+}
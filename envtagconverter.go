@@ -6,17 +6,34 @@ import (
 )
 
 const (
-	ENV_TAG = "env"
+	ENV_TAG         = "env"
+	ENV_FILE_SUFFIX = "_FILE"
 )
 
 type EnvTagConverter struct {
+	lookup EnvLookupFunc
 }
 
 // Set structure field with 'env' tag to value of environment variable.
+// If environment variable is not defined but variable with '_FILE' suffix
+// is defined (like 'DB_PASSWORD_FILE' for 'env:"DB_PASSWORD"') value is
+// read from the file which path is the variable value.
 // Returns:
 //   - Environment variable converter.
 func NewEnvTagConverter() TagConverterer {
-	return &EnvTagConverter{}
+	return &EnvTagConverter{lookup: os.LookupEnv}
+}
+
+// Set structure field with 'env' tag to value of environment variable
+// get by lookup function. For example DotEnv.LookupEnv allows to use
+// values from '.env' file.
+// Parameters:
+//   - lookup environment variables lookup function
+//
+// Returns:
+//   - Environment variable converter.
+func NewEnvTagConverterWithLookup(lookup EnvLookupFunc) TagConverterer {
+	return &EnvTagConverter{lookup: lookup}
 }
 
 // Returns conversion result.
@@ -32,8 +49,23 @@ func NewEnvTagConverter() TagConverterer {
 //   - Flag. If true value will be set. Otherwice it will be skiped
 //   - error in case of error
 func (conv *EnvTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
-	val, isExists := os.LookupEnv(tag)
-	return val, isExists, nil
+	lookup := conv.lookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	val, isExists := lookup(tag)
+	if isExists {
+		return val, true, nil
+	}
+	filePath, isExists := lookup(tag + ENV_FILE_SUFFIX)
+	if !isExists {
+		return val, false, nil
+	}
+	val, err := readValueFile(filePath)
+	if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
 }
 
 // Returns converter tag.
@@ -42,3 +74,10 @@ func (conv *EnvTagConverter) GetSimpleValue(tag string, t reflect.StructField, v
 func (conv EnvTagConverter) GetTag() string {
 	return ENV_TAG
 }
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv EnvTagConverter) HasEncryptedValues() bool {
+	return true
+}
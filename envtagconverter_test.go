@@ -2,6 +2,7 @@ package dynamictags
 
 import (
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -30,3 +31,47 @@ func TestEnvConverter(t *testing.T) {
 	assert.True(t, isSet)
 	assert.NoError(t, err)
 }
+
+func TestEnvConverterWithLookup(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		if key == TEST_ENV_STRING {
+			return TEST_ENV_VALUE, true
+		}
+		return "", false
+	}
+	conv := NewEnvTagConverterWithLookup(lookup)
+	assert.Equal(t, EXPECTED_ENV_TAG, conv.GetTag())
+	val, isSet, err := conv.GetSimpleValue(TEST_ENV_STRING, reflect.StructField{}, reflect.Value{}, "")
+	assert.Equal(t, TEST_ENV_VALUE, val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	_, isSet, err = conv.GetSimpleValue("UNKNOWN", reflect.StructField{}, reflect.Value{}, "")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+}
+
+func TestEnvConverterFile(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "password")
+	err := os.WriteFile(filePath, []byte(TEST_ENV_VALUE+"\n"), 0o600)
+	assert.NoError(t, err)
+	env := map[string]string{"DB_PASSWORD_FILE": filePath, "DB_USER": "user", "DB_USER_FILE": filePath, "DB_HOST_FILE": filepath.Join(dir, "unknown")}
+	lookup := func(key string) (string, bool) {
+		val, ok := env[key]
+		return val, ok
+	}
+	conv := NewEnvTagConverterWithLookup(lookup)
+	// Case 1 value from file without trailing new line
+	val, isSet, err := conv.GetSimpleValue("DB_PASSWORD", reflect.StructField{}, reflect.Value{}, "")
+	assert.Equal(t, TEST_ENV_VALUE, val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 environment variable has priority
+	val, isSet, err = conv.GetSimpleValue("DB_USER", reflect.StructField{}, reflect.Value{}, "")
+	assert.Equal(t, "user", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 3 file doesn't exist
+	_, _, err = conv.GetSimpleValue("DB_HOST", reflect.StructField{}, reflect.Value{}, "")
+	assert.Error(t, err)
+}
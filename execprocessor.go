@@ -0,0 +1,27 @@
+package dynamictags
+
+import "time"
+
+// Create processor to process 'exec' tag.
+// This processor replace structure field with 'exec' tag
+// by output of the command. Only commands from allowlist can be executed.
+// Example usage:
+//
+//	type Database struct {
+//	  Password string `exec:"pass show db/${ENV}/password"`
+//	}
+//	processor := NewExecProcessor([]string{"pass"}, 5*time.Second)
+//	processor.Process(&databaseConfiguration, nil)
+//
+// Parameters:
+//   - allowed allowed commands
+//   - timeout command timeout. If 0 default timeout is used
+//
+// Returns:
+//   - Command tag processor.
+func NewExecProcessor(allowed []string, timeout time.Duration) *DynamicTagProcessor {
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewExecTagConverter(allowed, timeout))
+	return &processor
+}
@@ -0,0 +1,62 @@
+package dynamictags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ExecTestStruct struct {
+	User     string `exec:"'${EXEC_TEST_BIN}' -test.run=TestExecHelperProcess -- stdout ${EXEC_TEST_USER}"`
+	Password string `exec:"'${EXEC_TEST_BIN}' -test.run=TestExecHelperProcess -- count '${EXEC_TEST_COUNT}'"`
+	Token    string `exec:"'${EXEC_TEST_BIN}' -test.run=TestExecHelperProcess -- count '${EXEC_TEST_COUNT}'"`
+}
+
+type ExecArgsTestStruct struct {
+	Args string `exec:"'${EXEC_TEST_BIN}' -test.run=TestExecHelperProcess -- stdout ${EXEC_TEST_USER} end"`
+}
+
+func TestExecProcessor(t *testing.T) {
+	t.Setenv(EXEC_HELPER_ENV, "1")
+	countFile := filepath.Join(t.TempDir(), "count")
+	processor := NewExecProcessor([]string{os.Args[0]}, 0)
+	processor.SetDictionaryValue("EXEC_TEST_BIN", os.Args[0])
+	processor.SetDictionaryValue("EXEC_TEST_USER", "admin")
+	processor.SetDictionaryValue("EXEC_TEST_COUNT", countFile)
+	// Case 1 the same command is executed once
+	data := ExecTestStruct{}
+	err := processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ExecTestStruct{User: "admin", Password: "counted", Token: "counted"}, data)
+	content, err := os.ReadFile(countFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "x", string(content))
+	// Case 2 command is not allowed
+	processor = NewExecProcessor([]string{"pass"}, 0)
+	processor.SetDictionaryValue("EXEC_TEST_BIN", os.Args[0])
+	err = processor.Process(&data, nil)
+	assert.Error(t, err)
+}
+
+func TestExecProcessorArguments(t *testing.T) {
+	t.Setenv(EXEC_HELPER_ENV, "1")
+	processor := NewExecProcessor([]string{os.Args[0]}, 0)
+	processor.SetDictionaryValue("EXEC_TEST_BIN", os.Args[0])
+	// Case 1 value with spaces is one argument
+	processor.SetDictionaryValue("EXEC_TEST_USER", "admin --force")
+	data := ExecArgsTestStruct{}
+	err := processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin --force|end", data.Args)
+	// Case 2 quotes in value are not parsed
+	processor.SetDictionaryValue("EXEC_TEST_USER", `a' 'b "c`)
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `a' 'b "c|end`, data.Args)
+	// Case 3 command can't be substituted by value with arguments
+	processor.SetDictionaryValue("EXEC_TEST_BIN", os.Args[0]+" -test.run=TestExecHelperProcess")
+	err = processor.Process(&data, nil)
+	assert.ErrorContains(t, err, "not allowed")
+}
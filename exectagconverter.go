@@ -0,0 +1,213 @@
+package dynamictags
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EXEC_TAG             = "exec"
+	EXEC_DEFAULT_TIMEOUT = 10 * time.Second
+)
+
+type ExecTagConverter struct {
+	allowed []string
+	timeout time.Duration
+	mutex   *sync.Mutex
+	// Commands output cache. Is not nil in converter of Process call only
+	cache map[string]string
+}
+
+// Set structure field with 'exec' tag to output of the command. Tag value
+// is command line (like 'exec:"pass show db/${ENV}/password"'). Command is
+// executed without shell. Arguments are separated by spaces, single and
+// double quotes can be used for arguments with spaces. Command line is split
+// to arguments before placeholders substitution, so substituted value is
+// always a part of one argument (even if it contains spaces or quotes) and
+// can't add new arguments. Leading and trailing
+// whitespaces are removed from command output. Only commands from the
+// allowlist can be executed. Every unique command is executed only once
+// during one Process call.
+// Parameters:
+//   - allowed allowed commands (command names or paths exactly as in tags)
+//   - timeout command timeout. If 0 default timeout (10 seconds) is used
+//
+// Returns:
+//   - Command tag converter.
+func NewExecTagConverter(allowed []string, timeout time.Duration) TagConverterer {
+	if timeout <= 0 {
+		timeout = EXEC_DEFAULT_TIMEOUT
+	}
+	return &ExecTagConverter{allowed: allowed, timeout: timeout, mutex: &sync.Mutex{}}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *ExecTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	args, err := splitCommandLine(tag)
+	if err != nil {
+		return nil, false, err
+	}
+	return conv.getValue(args, t, path)
+}
+
+// Returns conversion result. Command line is split to arguments and then
+// placeholders are substituted in each argument.
+// Parameters:
+//   - tag raw tag value
+//   - resolve placeholders substitution function
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *ExecTagConverter) GetRawValue(tag string, resolve func(str string) (string, error), t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	args, err := splitCommandLine(tag)
+	if err != nil {
+		return nil, false, err
+	}
+	for i, arg := range args {
+		args[i], err = resolve(arg)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return conv.getValue(args, t, path)
+}
+
+func (conv *ExecTagConverter) getValue(args []string, t reflect.StructField, path string) (any, bool, error) {
+	var err error
+	if len(args) == 0 {
+		return nil, false, errors.New("command is expected. Path: " + path + "." + t.Name)
+	}
+	if !slices.Contains(conv.allowed, args[0]) {
+		return nil, false, errors.New("command '" + args[0] + "' is not allowed")
+	}
+	key := strings.Join(args, "\x00")
+	conv.mutex.Lock()
+	val, ok := conv.cache[key]
+	conv.mutex.Unlock()
+	if ok {
+		return val, true, nil
+	}
+	val, err = conv.run(args)
+	if err != nil {
+		return nil, false, err
+	}
+	conv.mutex.Lock()
+	if conv.cache != nil {
+		conv.cache[key] = val
+	}
+	conv.mutex.Unlock()
+	return val, true, nil
+}
+
+func (conv *ExecTagConverter) run(args []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), conv.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	// Don't wait for child processes which keep output open
+	cmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return "", errors.New("command '" + args[0] + "' timed out after " + conv.timeout.String())
+	}
+	if err != nil {
+		msg := "command '" + args[0] + "' failed: " + err.Error()
+		errOutput := strings.TrimSpace(stderr.String())
+		if errOutput != "" {
+			msg += ": " + errOutput
+		}
+		return "", errors.New(msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Returns converter with commands output cache for one Process call.
+// Returns:
+//   - converter of Process call
+func (conv *ExecTagConverter) BeginProcess() TagConverterer {
+	return &ExecTagConverter{allowed: conv.allowed, timeout: conv.timeout, mutex: &sync.Mutex{}, cache: make(map[string]string)}
+}
+
+// Finish Process call. Cache of the call is dropped with its converter.
+// Parameters:
+//   - converter converter of Process call
+func (conv *ExecTagConverter) EndProcess(converter TagConverterer) {
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv ExecTagConverter) GetTag() string {
+	return EXEC_TAG
+}
+
+// Split command line to arguments. Arguments are separated by whitespaces.
+// Quoted (by single or double quotes) parts are not split. Backslash escapes
+// next character outside of single quotes.
+func splitCommandLine(cmdLine string) ([]string, error) {
+	args := make([]string, 0)
+	var builder strings.Builder
+	hasArg := false
+	var quote rune
+	escaped := false
+	for _, ch := range cmdLine {
+		switch {
+		case escaped:
+			builder.WriteRune(ch)
+			escaped = false
+		case ch == '\\' && quote != '\'':
+			escaped = true
+			hasArg = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else {
+				builder.WriteRune(ch)
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+			hasArg = true
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			if hasArg {
+				args = append(args, builder.String())
+				builder.Reset()
+				hasArg = false
+			}
+		default:
+			builder.WriteRune(ch)
+			hasArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("incorrect command line '" + cmdLine + "'")
+	}
+	if hasArg {
+		args = append(args, builder.String())
+	}
+	return args, nil
+}
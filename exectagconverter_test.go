@@ -0,0 +1,104 @@
+package dynamictags
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_EXEC_TAG = "exec"
+	EXEC_HELPER_ENV   = "EXEC_TEST_HELPER"
+)
+
+// Command used by tests. Test binary is started with '-test.run=TestExecHelperProcess'.
+func TestExecHelperProcess(t *testing.T) {
+	if os.Getenv(EXEC_HELPER_ENV) != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	args = args[1:]
+	switch args[0] {
+	case "stdout":
+		fmt.Println("  " + strings.Join(args[1:], "|") + "  ")
+	case "fail":
+		fmt.Fprintln(os.Stderr, args[1])
+		os.Exit(2)
+	case "sleep":
+		time.Sleep(5 * time.Second)
+	case "count":
+		file, _ := os.OpenFile(args[1], os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		file.WriteString("x")
+		file.Close()
+		fmt.Println("counted")
+	}
+	os.Exit(0)
+}
+
+func getExecHelperCommand(args string) string {
+	return "'" + os.Args[0] + "' -test.run=TestExecHelperProcess -- " + args
+}
+
+func TestExecConverter(t *testing.T) {
+	t.Setenv(EXEC_HELPER_ENV, "1")
+	conv := NewExecTagConverter([]string{os.Args[0]}, 0)
+	assert.Equal(t, EXPECTED_EXEC_TAG, conv.GetTag())
+	// Case 1 trimmed output, quoted arguments
+	val, isSet, err := conv.GetSimpleValue(getExecHelperCommand(`stdout "a b" 'c d' e\ f`), reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, "a b|c d|e f", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 stderr is included into error
+	_, _, err = conv.GetSimpleValue(getExecHelperCommand("fail 'access denied'"), reflect.StructField{}, reflect.Value{}, "$")
+	assert.ErrorContains(t, err, "access denied")
+	// Case 3 timeout
+	timeoutConv := NewExecTagConverter([]string{os.Args[0]}, 500*time.Millisecond)
+	_, _, err = timeoutConv.GetSimpleValue(getExecHelperCommand("sleep"), reflect.StructField{}, reflect.Value{}, "$")
+	assert.ErrorContains(t, err, "timed out")
+	// Case 4 command is not allowed
+	_, _, err = conv.GetSimpleValue("cat /etc/passwd", reflect.StructField{}, reflect.Value{}, "$")
+	assert.ErrorContains(t, err, "not allowed")
+	// Case 5 incorrect command line
+	_, _, err = conv.GetSimpleValue(getExecHelperCommand("stdout 'a"), reflect.StructField{}, reflect.Value{}, "$")
+	assert.Error(t, err)
+	_, _, err = conv.GetSimpleValue(" ", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Error(t, err)
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	args, err := splitCommandLine(` pass  show "db/my password" 'a\b' c\ d "" `)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pass", "show", "db/my password", `a\b`, "c d", ""}, args)
+	_, err = splitCommandLine(`pass "show`)
+	assert.Error(t, err)
+	_, err = splitCommandLine(`pass show\`)
+	assert.Error(t, err)
+}
+
+func TestExecConverterCache(t *testing.T) {
+	t.Setenv(EXEC_HELPER_ENV, "1")
+	countFile := filepath.Join(t.TempDir(), "count")
+	conv := NewExecTagConverter([]string{os.Args[0]}, 0)
+	tag := getExecHelperCommand("count '" + countFile + "'")
+	listener := conv.(ProcessListener)
+	processConv := listener.BeginProcess()
+	for i := 0; i < 3; i++ {
+		val, isSet, err := processConv.GetSimpleValue(tag, reflect.StructField{}, reflect.Value{}, "$")
+		assert.Equal(t, "counted", val)
+		assert.True(t, isSet)
+		assert.NoError(t, err)
+	}
+	listener.EndProcess(processConv)
+	content, err := os.ReadFile(countFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "x", string(content))
+}
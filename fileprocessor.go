@@ -0,0 +1,49 @@
+package dynamictags
+
+// Create processor to process 'file' tag.
+// This processor replace structure field with 'file' tag
+// by content of the file (like Docker secret). Example usage:
+//
+//	type Database struct {
+//	  Password string `file:"/run/secrets/${SERVICE}_db_password"`
+//	}
+//	processor := NewFileProcessor()
+//	processor.Process(&databaseConfiguration, nil)
+//
+// Returns:
+//   - File tag processor.
+func NewFileProcessor() *DynamicTagProcessor {
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewFileTagConverter())
+	return &processor
+}
+
+// Create processor to process 'filedir' tag.
+// This processor replace structure field with 'filedir' tag
+// by content of the file from directory (like mounted Kubernetes Secret).
+// Example usage:
+//
+//	type Database struct {
+//	  User     string `filedir:"db_user"`
+//	  Password string `filedir:"db_password"`
+//	}
+//	processor, err := NewFileDirProcessor("/etc/secrets")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&databaseConfiguration, nil)
+//
+// Returns:
+//   - File directory tag processor if success.
+//   - error if directory can't be read
+func NewFileDirProcessor(dir string) (*DynamicTagProcessor, error) {
+	converter, err := NewFileDirTagConverter(dir)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(converter)
+	return &processor, nil
+}
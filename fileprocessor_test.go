@@ -0,0 +1,56 @@
+package dynamictags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type FileProcessorTestStruct struct {
+	Password string `file:"${FILE_TEST_DIR}/${FILE_TEST_SERVICE}_db_password" filedir:"db_password" default:"empty"`
+	Database struct {
+		Port int `filedir:"port" default:"1"`
+	} `filedir:"database"`
+}
+
+func TestFileProcessor(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "api_db_password"), []byte(TEST_FILE_VALUE+"\n"), 0o600)
+	assert.NoError(t, err)
+	processor := NewFileProcessor()
+	processor.SetDictionaryValue("FILE_TEST_DIR", dir)
+	processor.SetDictionaryValue("FILE_TEST_SERVICE", "api")
+	processor.AddTagConverter(NewDefaultTagConverter())
+	// Case 1 file exists
+	data := FileProcessorTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, TEST_FILE_VALUE, data.Password)
+	// Case 2 file doesn't exist
+	processor.SetDictionaryValue("FILE_TEST_SERVICE", "web")
+	data = FileProcessorTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "empty", data.Password)
+}
+
+func TestFileDirProcessor(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "db_password"), []byte(TEST_FILE_VALUE), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "database.port"), []byte("5432\n"), 0o600)
+	assert.NoError(t, err)
+	// Case 1 directory exists
+	processor, err := NewFileDirProcessor(dir)
+	assert.NoError(t, err)
+	data := FileProcessorTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, TEST_FILE_VALUE, data.Password)
+	assert.Equal(t, 5432, data.Database.Port)
+	// Case 2 directory doesn't exist
+	_, err = NewFileDirProcessor(filepath.Join(dir, "unknown"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,165 @@
+package dynamictags
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+const (
+	FILE_TAG     = "file"
+	FILE_DIR_TAG = "filedir"
+)
+
+type FileTagConverter struct {
+}
+
+type FileDirTagConverter struct {
+	values map[string]string
+}
+
+// Set structure field with 'file' tag to content of the file. Tag value is
+// path to the file (like 'file:"/run/secrets/${SERVICE}_db_password"').
+// Trailing new line is removed from the content. If file doesn't exist
+// field is skipped.
+// Returns:
+//   - File tag converter.
+func NewFileTagConverter() TagConverterer {
+	return &FileTagConverter{}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *FileTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	val, err := readValueFile(tag)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv FileTagConverter) GetTag() string {
+	return FILE_TAG
+}
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv FileTagConverter) HasEncryptedValues() bool {
+	return true
+}
+
+// Set structure field with 'filedir' tag to content of the file from
+// directory (like mounted Kubernetes ConfigMap or Secret). Tag value is
+// file name. Keys of nested structures are composed in the same way as
+// json paths. I.e. if structure field has tag 'filedir:"database"' tag
+// 'filedir:"port"' of its field means file 'database.port'.
+// Parameters:
+//   - dir directory path
+//
+// Returns:
+//   - File directory tag converter.
+//   - error if directory can't be read
+func NewFileDirTagConverter(dir string) (TagConverterer, error) {
+	values, err := ReadFileDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &FileDirTagConverter{values: values}, nil
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *FileDirTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	val, ok := conv.values[composeFlatKey(tag, path)]
+	return val, ok, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv FileDirTagConverter) GetTag() string {
+	return FILE_DIR_TAG
+}
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv FileDirTagConverter) HasEncryptedValues() bool {
+	return true
+}
+
+// Read all files of directory. Hidden files and subdirectories are skipped
+// (Kubernetes keeps service files like '..data' in mounted directories).
+// Trailing new line is removed from files content.
+// Parameters:
+//   - dir directory path
+//
+// Returns:
+//   - map of file name to file content
+//   - error if directory or file can't be read
+func ReadFileDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		filePath := filepath.Join(dir, entry.Name())
+		// Symbolic links are followed
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		res[entry.Name()], err = readValueFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Read file content without trailing new line.
+func readValueFile(filePath string) (string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	res := strings.TrimSuffix(string(content), "\n")
+	return strings.TrimSuffix(res, "\r"), nil
+}
@@ -0,0 +1,79 @@
+package dynamictags
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_FILE_TAG     = "file"
+	EXPECTED_FILE_DIR_TAG = "filedir"
+	TEST_FILE_VALUE       = "secret"
+)
+
+func TestFileConverter(t *testing.T) {
+	dir := t.TempDir()
+	conv := NewFileTagConverter()
+	assert.Equal(t, EXPECTED_FILE_TAG, conv.GetTag())
+	// Case 1 trailing new line is removed
+	filePath := filepath.Join(dir, "unix")
+	err := os.WriteFile(filePath, []byte(TEST_FILE_VALUE+"\n"), 0o600)
+	assert.NoError(t, err)
+	val, isSet, err := conv.GetSimpleValue(filePath, reflect.StructField{}, reflect.Value{}, "")
+	assert.Equal(t, TEST_FILE_VALUE, val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 windows new line is removed, other new lines are kept
+	filePath = filepath.Join(dir, "windows")
+	err = os.WriteFile(filePath, []byte("line1\r\nline2\r\n"), 0o600)
+	assert.NoError(t, err)
+	val, isSet, err = conv.GetSimpleValue(filePath, reflect.StructField{}, reflect.Value{}, "")
+	assert.Equal(t, "line1\r\nline2", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 3 file doesn't exist
+	_, isSet, err = conv.GetSimpleValue(filepath.Join(dir, "unknown"), reflect.StructField{}, reflect.Value{}, "")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+	// Case 4 directory can't be read as file
+	_, _, err = conv.GetSimpleValue(dir, reflect.StructField{}, reflect.Value{}, "")
+	assert.Error(t, err)
+}
+
+func TestReadFileDir(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "db_user"), []byte("user\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "database.port"), []byte("5432"), 0o600)
+	assert.NoError(t, err)
+	err = os.Mkdir(filepath.Join(dir, "..2024_01_01"), 0o700)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "..2024_01_01", "db_password"), []byte(TEST_FILE_VALUE), 0o600)
+	assert.NoError(t, err)
+	err = os.Symlink(filepath.Join("..2024_01_01", "db_password"), filepath.Join(dir, "db_password"))
+	assert.NoError(t, err)
+	err = os.Mkdir(filepath.Join(dir, "subdir"), 0o700)
+	assert.NoError(t, err)
+	// Case 1 hidden files and directories are skipped, links are followed
+	values, err := ReadFileDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"db_user": "user", "db_password": TEST_FILE_VALUE, "database.port": "5432"}, values)
+	// Case 2 directory doesn't exist
+	_, err = ReadFileDir(filepath.Join(dir, "unknown"))
+	assert.Error(t, err)
+	// Case 3 converter
+	conv, err := NewFileDirTagConverter(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, EXPECTED_FILE_DIR_TAG, conv.GetTag())
+	val, isSet, err := conv.GetSimpleValue("port", reflect.StructField{}, reflect.Value{}, "$.database")
+	assert.Equal(t, "5432", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	_, isSet, err = conv.GetSimpleValue("unknown", reflect.StructField{}, reflect.Value{}, "$")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+}
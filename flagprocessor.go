@@ -0,0 +1,47 @@
+package dynamictags
+
+import (
+	"os"
+)
+
+// Create processor to process 'flag', 'env' and 'default' tags.
+// Command line flag has the highest priority, then environment variable
+// and then default value. Placeholders of flag names are resolved by
+// environment variables only, so dictionary values set after processor
+// creation don't change flag names (see NewFlagTagConverter). Example usage:
+//
+//	type Config struct {
+//	  Database struct {
+//	    Port int `flag:"port" env:"DB_PORT" default:"5432" usage:"database port"`
+//	  } `flag:"db"`
+//	}
+//	config := Config{}
+//	processor, err := NewFlagProcessor(&config, nil)
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&config, nil)
+//
+// Parameters:
+//   - data pointer to structure which flags are created for
+//   - args command line arguments without program name. If nil os.Args[1:]
+//     is used
+//
+// Returns:
+//   - Flag tag processor if success.
+//   - error if arguments can't be parsed (flag.ErrHelp if help is requested)
+func NewFlagProcessor(data any, args []string) (*DynamicTagProcessor, error) {
+	if args == nil {
+		args = os.Args[1:]
+	}
+	converter, err := NewFlagTagConverter(data, args, nil)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(converter)
+	processor.AddTagConverter(NewEnvTagConverter())
+	processor.AddTagConverter(NewDefaultTagConverter())
+	return &processor, nil
+}
@@ -0,0 +1,46 @@
+package dynamictags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagProcessor(t *testing.T) {
+	t.Setenv("FLAG_TEST_HOST", "env.host")
+	t.Setenv("FLAG_TEST_PREFIX", "FLAG_TEST")
+	t.Setenv("FLAG_TEST_DB_PORT", "7777")
+	// Case 1 flag has priority over environment variable
+	data := FlagTestStruct{}
+	processor, err := NewFlagProcessor(&data, []string{"--host", "flag.host", "--db.tags", "a,b"})
+	assert.NoError(t, err)
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "flag.host", data.Host)
+	assert.Equal(t, 7777, data.Database.Port)
+	assert.Equal(t, []string{"a", "b"}, data.Database.Tags)
+	assert.False(t, data.Debug)
+	// Case 2 environment variable and default value are used if flag is not passed
+	t.Setenv("FLAG_TEST_DB_PORT", "")
+	data = FlagTestStruct{}
+	processor, err = NewFlagProcessor(&data, []string{"--debug"})
+	assert.NoError(t, err)
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "env.host", data.Host)
+	assert.True(t, data.Debug)
+	// Case 3 incorrect arguments
+	_, err = NewFlagProcessor(&data, []string{"--db.port"})
+	assert.Error(t, err)
+	// Case 4 last value of repeated flag is used, slice flag values are joined
+	repeated := struct {
+		Port int   `flag:"port"`
+		Ids  []int `flag:"id"`
+	}{}
+	processor, err = NewFlagProcessor(&repeated, []string{"--port", "1", "--id", "1", "--port", "2", "--id", "2,3"})
+	assert.NoError(t, err)
+	err = processor.Process(&repeated, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, repeated.Port)
+	assert.Equal(t, []int{1, 2, 3}, repeated.Ids)
+}
@@ -0,0 +1,211 @@
+package dynamictags
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+)
+
+const (
+	FLAG_TAG       = "flag"
+	FLAG_USAGE_TAG = "usage"
+)
+
+type FlagTagConverter struct {
+	values map[string]string
+}
+
+// Value of command line flag. Repeated values of slice flag are joined by
+// ',' (i.e. '--tags a --tags b' is the same as '--tags a,b'). The last
+// value of other repeated flags is used.
+type flagValue struct {
+	value   string
+	isBool  bool
+	isSlice bool
+	isSet   bool
+}
+
+func (val *flagValue) String() string {
+	if val == nil {
+		return ""
+	}
+	return val.value
+}
+
+func (val *flagValue) Set(value string) error {
+	if val.isSet && val.isSlice {
+		value = val.value + "," + value
+	}
+	val.value = value
+	val.isSet = true
+	return nil
+}
+
+func (val *flagValue) IsBoolFlag() bool {
+	return val.isBool
+}
+
+// Set structure field with 'flag' tag to value of command line flag.
+// Flags are created for all fields with 'flag' tag. Flag names of nested
+// structures are composed in the same way as json paths. I.e. if structure
+// field has tag 'flag:"db"' tag 'flag:"port"' of its field means flag
+// '--db.port'. Tag started with '$.' is absolute name. Only flags passed in
+// command line are set, so flag converter can be combined with other
+// converters. Help ('--help') contains flag type, description from 'usage'
+// tag, value of 'default' tag and name of environment variable from 'env'
+// tag. Flag set is created with flag.ContinueOnError error handling. If help
+// is requested flag.ErrHelp is returned. Flag names are resolved by the
+// dictionary passed to the constructor, so tags with placeholders (like
+// 'flag:"${SERVICE}.port"') need the same dictionary as the processor.
+// Example usage:
+//
+//	type Config struct {
+//	  Port int `flag:"port" env:"PORT" default:"8080" usage:"server port"`
+//	}
+//	converter, err := NewFlagTagConverter(&config, os.Args[1:], nil)
+//
+// Parameters:
+//   - data pointer to structure (or structure) which flags are created for
+//   - args command line arguments without program name
+//   - dictionary dictionary for tags processing. Should be the same as
+//     dictionary of the processor
+//
+// Returns:
+//   - Flag tag converter.
+//   - error if arguments can't be parsed or two fields have the same flag name
+func NewFlagTagConverter(data any, args []string, dictionary map[string]string) (TagConverterer, error) {
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	return NewFlagTagConverterWithFlagSet(flagSet, data, args, dictionary)
+}
+
+// Create flag tag converter with the flag set. The same as NewFlagTagConverter
+// but flags are added to the flag set. It allows to set program name, help
+// output and error handling or to define additional flags.
+// Parameters:
+//   - flagSet flag set
+//   - data pointer to structure (or structure) which flags are created for
+//   - args command line arguments without program name
+//   - dictionary dictionary for tags processing. Should be the same as
+//     dictionary of the processor
+//
+// Returns:
+//   - Flag tag converter.
+//   - error if arguments can't be parsed or flag name is already defined
+func NewFlagTagConverterWithFlagSet(flagSet *flag.FlagSet, data any, args []string, dictionary map[string]string) (TagConverterer, error) {
+	t := reflect.TypeOf(data)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("pointer to structure is expected")
+	}
+	values := make(map[string]*flagValue)
+	err := addStructFlags(flagSet, t, "$", dictionary, values)
+	if err != nil {
+		return nil, err
+	}
+	err = flagSet.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	conv := FlagTagConverter{values: make(map[string]string)}
+	for name, val := range values {
+		if val.isSet {
+			conv.values[name] = val.value
+		}
+	}
+	return &conv, nil
+}
+
+func addStructFlags(flagSet *flag.FlagSet, t reflect.Type, path string, dictionary map[string]string, values map[string]*flagValue) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tagVal, err := ProcessString(field.Tag.Get(FLAG_TAG), dictionary)
+		if err != nil {
+			return err
+		}
+		if field.Type.Kind() == reflect.Struct && field.Type != timeType {
+			if tagVal == "" {
+				tagVal = field.Name
+			}
+			err = addStructFlags(flagSet, field.Type, composeTreePath(tagVal, path), dictionary, values)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if tagVal == "" {
+			continue
+		}
+		usage, err := getFlagUsage(field, dictionary)
+		if err != nil {
+			return err
+		}
+		name := composeFlatKey(tagVal, path)
+		if flagSet.Lookup(name) != nil {
+			return errors.New("flag '" + name + "' is defined twice. Field: " + path + "." + field.Name)
+		}
+		val := &flagValue{isBool: field.Type.Kind() == reflect.Bool, isSlice: field.Type.Kind() == reflect.Slice}
+		values[name] = val
+		flagSet.Var(val, name, usage)
+	}
+	return nil
+}
+
+// Returns flag help. Type is enclosed in back quotes to be used as flag
+// argument name by flag.PrintDefaults.
+func getFlagUsage(field reflect.StructField, dictionary map[string]string) (string, error) {
+	details := make([]string, 0)
+	if field.Type.Kind() != reflect.Bool {
+		details = append(details, "`"+field.Type.String()+"`")
+	}
+	defaultVal, ok := field.Tag.Lookup(DEFAULT_TAG)
+	if ok {
+		defaultVal, err := ProcessString(defaultVal, dictionary)
+		if err != nil {
+			return "", err
+		}
+		details = append(details, "default "+defaultVal)
+	}
+	envName := field.Tag.Get(ENV_TAG)
+	if envName != "" {
+		envName, err := ProcessString(envName, dictionary)
+		if err != nil {
+			return "", err
+		}
+		details = append(details, "env "+envName)
+	}
+	usage := field.Tag.Get(FLAG_USAGE_TAG)
+	if len(details) > 0 {
+		usage += " (" + strings.Join(details, ", ") + ")"
+	}
+	return strings.TrimSpace(usage), nil
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *FlagTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	val, ok := conv.values[composeFlatKey(tag, path)]
+	return val, ok, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv FlagTagConverter) GetTag() string {
+	return FLAG_TAG
+}
@@ -0,0 +1,103 @@
+package dynamictags
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_FLAG_TAG = "flag"
+)
+
+type FlagTestStruct struct {
+	Host     string `flag:"host" env:"FLAG_TEST_HOST" default:"localhost" usage:"server host"`
+	Debug    bool   `flag:"debug" usage:"debug mode"`
+	Internal string
+	Database struct {
+		Port int      `flag:"port" env:"${FLAG_TEST_PREFIX}_DB_PORT" default:"5432"`
+		Tags []string `flag:"tags"`
+	} `flag:"db"`
+	Log struct {
+		Level string `flag:"$.log-level"`
+	}
+}
+
+func TestFlagConverter(t *testing.T) {
+	data := FlagTestStruct{}
+	args := []string{"--db.port", "6543", "-debug", "--db.tags", "a", "--db.tags=b", "--log-level", "warn"}
+	conv, err := NewFlagTagConverter(&data, args, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, EXPECTED_FLAG_TAG, conv.GetTag())
+	// Case 1 nested structure flag
+	val, isSet, err := conv.GetSimpleValue("port", reflect.StructField{}, reflect.Value{}, "$.db")
+	assert.Equal(t, "6543", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 bool flag
+	val, isSet, _ = conv.GetSimpleValue("debug", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, "true", val)
+	assert.True(t, isSet)
+	// Case 3 repeated flag
+	val, _, _ = conv.GetSimpleValue("tags", reflect.StructField{}, reflect.Value{}, "$.db")
+	assert.Equal(t, "a,b", val)
+	// Case 4 absolute name
+	val, isSet, _ = conv.GetSimpleValue("$.log-level", reflect.StructField{}, reflect.Value{}, "$.Log")
+	assert.Equal(t, "warn", val)
+	assert.True(t, isSet)
+	// Case 5 flag is not passed
+	_, isSet, err = conv.GetSimpleValue("host", reflect.StructField{}, reflect.Value{}, "$")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+	// Case 6 unknown flag
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	_, err = NewFlagTagConverterWithFlagSet(flagSet, &data, []string{"--unknown"}, nil)
+	assert.Error(t, err)
+	// Case 7 not a structure
+	_, err = NewFlagTagConverter(&args, nil, nil)
+	assert.Error(t, err)
+	// Case 8 two fields have the same flag name
+	type FlagDbStruct struct {
+		Port int `flag:"port"`
+	}
+	duplicated := struct {
+		Database FlagDbStruct `flag:"db"`
+		DbPort   int          `flag:"db.port"`
+	}{}
+	assert.NotPanics(t, func() {
+		_, err = NewFlagTagConverter(&duplicated, nil, nil)
+	})
+	assert.Error(t, err)
+	sameType := struct {
+		Primary FlagDbStruct
+		Replica FlagDbStruct `flag:"Primary"`
+	}{}
+	_, err = NewFlagTagConverter(&sameType, nil, nil)
+	assert.Error(t, err)
+	// Case 9 flag is already defined in the flag set
+	flagSet = flag.NewFlagSet("test", flag.ContinueOnError)
+	flagSet.String("host", "", "")
+	_, err = NewFlagTagConverterWithFlagSet(flagSet, &data, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestFlagConverterHelp(t *testing.T) {
+	data := FlagTestStruct{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	output := bytes.Buffer{}
+	flagSet.SetOutput(&output)
+	_, err := NewFlagTagConverterWithFlagSet(flagSet, &data, []string{"--help"}, map[string]string{"FLAG_TEST_PREFIX": "APP"})
+	assert.True(t, errors.Is(err, flag.ErrHelp))
+	help := output.String()
+	assert.Contains(t, help, "-host string\n    \tserver host (string, default localhost, env FLAG_TEST_HOST)")
+	assert.Contains(t, help, "-debug\n    \tdebug mode\n")
+	assert.Contains(t, help, "-db.port int\n    \t(int, default 5432, env APP_DB_PORT)")
+	assert.Contains(t, help, "-db.tags []string\n    \t([]string)\n")
+	assert.NotContains(t, help, "Internal")
+}
@@ -0,0 +1,368 @@
+package dynamictags
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	HTTP_DEFAULT_TIMEOUT     = 10 * time.Second
+	HTTP_DEFAULT_RETRY_DELAY = 500 * time.Millisecond
+	HTTP_DEFAULT_MAX_SIZE    = 10 * 1024 * 1024
+)
+
+var contentTypeFormats = map[string]ConfigFormat{
+	"application/json":   FORMAT_JSON,
+	"application/json5":  FORMAT_JSON5,
+	"application/yaml":   FORMAT_YAML,
+	"application/x-yaml": FORMAT_YAML,
+	"text/yaml":          FORMAT_YAML,
+	"text/x-yaml":        FORMAT_YAML,
+	"application/toml":   FORMAT_TOML,
+	"application/xml":    FORMAT_XML,
+	"text/xml":           FORMAT_XML,
+}
+
+// Configuration document fetched from http(s) server. Source remembers
+// ETag of the last response and sends 'If-None-Match' header, so not
+// modified document is not transferred again. If server is unreachable
+// last known good document is used. The document is kept in memory and
+// optionally in cache file on disk (see SetCacheFile).
+type HttpSource struct {
+	url        string
+	client     *http.Client
+	timeout    time.Duration
+	maxSize    int64
+	dictionary map[string]string
+	retries    int
+	retryDelay time.Duration
+	cacheFile  string
+	mutex      sync.Mutex
+	// Url of the last fetched document (placeholders are processed)
+	docUrl      string
+	etag        string
+	content     []byte
+	contentType string
+	lastErr     error
+	cacheErr    error
+}
+
+// Error returned by server (response status is not 200 or 304).
+type HttpStatusError struct {
+	Url        string
+	StatusCode int
+}
+
+func (err *HttpStatusError) Error() string {
+	return "unexpected status " + strconv.Itoa(err.StatusCode) + " of '" + err.Url + "'"
+}
+
+// Create http configuration source.
+// Parameters:
+//   - docUrl document url. Url can contain placeholders (like
+//     'https://config.local/${SERVICE}/${ENV}.yaml') which are processed by
+//     ProcessString on every fetch
+//
+// Returns:
+//   - http source
+func NewHttpSource(docUrl string) *HttpSource {
+	return &HttpSource{
+		url:        docUrl,
+		client:     &http.Client{},
+		timeout:    HTTP_DEFAULT_TIMEOUT,
+		maxSize:    HTTP_DEFAULT_MAX_SIZE,
+		retryDelay: HTTP_DEFAULT_RETRY_DELAY,
+	}
+}
+
+// Set http client. Client can be used to configure TLS or proxy. Client
+// is not modified, request timeout is set by SetTimeout.
+// Parameters:
+//   - client http client
+func (source *HttpSource) SetClient(client *http.Client) {
+	source.client = client
+}
+
+// Set request timeout.
+// Parameters:
+//   - timeout timeout of one request (including retries each request has the timeout)
+func (source *HttpSource) SetTimeout(timeout time.Duration) {
+	source.timeout = timeout
+}
+
+// Set maximal size of the document. Larger document causes fetch error.
+// Parameters:
+//   - maxSize maximal size of the document in bytes. 0 means no limit
+func (source *HttpSource) SetMaxSize(maxSize int64) {
+	source.maxSize = maxSize
+}
+
+// Set dictionary for url placeholders processing.
+// Parameters:
+//   - dict dictionary
+func (source *HttpSource) SetDictionary(dict map[string]string) {
+	source.dictionary = dict
+}
+
+// Set retries. Request is retried if server is unreachable or returns
+// 5xx status. Delay is doubled after every retry.
+// Parameters:
+//   - retries number of retries. 0 means no retries
+//   - delay delay before the first retry
+func (source *HttpSource) SetRetries(retries int, delay time.Duration) {
+	source.retries = retries
+	source.retryDelay = delay
+}
+
+// Set cache file. Every fetched document is saved to the file. If server
+// is unreachable and document was not fetched yet, document is read from
+// the file. Path can contain placeholders which are processed in the same
+// way as url placeholders. If url depends on placeholders the path should
+// depend on them too, so documents of different urls are cached separately.
+// Parameters:
+//   - path path to the cache file (like '/var/cache/app/${ENV}.yaml')
+func (source *HttpSource) SetCacheFile(path string) {
+	source.cacheFile = path
+}
+
+// Returns error of the last fetch if last known good document was used
+// instead of the server response.
+// Returns:
+//   - error of the last fetch or nil if document was received from server
+func (source *HttpSource) LastError() error {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return source.lastErr
+}
+
+// Returns error of the last cache file writing. Cache file writing error
+// doesn't fail the fetch.
+// Returns:
+//   - error of the last cache file writing or nil if file was written
+func (source *HttpSource) CacheError() error {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return source.cacheErr
+}
+
+// Fetch the document. If server returns 304 (not modified) the previous
+// document is returned. If server is unreachable (or returns 5xx status)
+// after all retries last known good document is returned.
+// Returns:
+//   - document content
+//   - error if document can't be fetched and there is no last known good document
+func (source *HttpSource) Fetch() ([]byte, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	docUrl, err := ProcessString(source.url, source.dictionary)
+	if err != nil {
+		return nil, err
+	}
+	cacheFile, err := ProcessString(source.cacheFile, source.dictionary)
+	if err != nil {
+		return nil, err
+	}
+	if docUrl != source.docUrl {
+		// ETag and last known good document belong to other document
+		source.docUrl = docUrl
+		source.etag = ""
+		source.content = nil
+		source.contentType = ""
+	}
+	err = source.fetch(docUrl, cacheFile)
+	if err == nil {
+		source.lastErr = nil
+		return source.content, nil
+	}
+	var statusErr *HttpStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode < http.StatusInternalServerError {
+		// Server is reachable but document is incorrect
+		return nil, err
+	}
+	source.lastErr = err
+	if source.content != nil {
+		return source.content, nil
+	}
+	if cacheFile != "" {
+		content, cacheErr := os.ReadFile(cacheFile)
+		if cacheErr == nil {
+			source.content = content
+			return content, nil
+		}
+	}
+	return nil, err
+}
+
+func (source *HttpSource) fetch(docUrl string, cacheFile string) error {
+	delay := source.retryDelay
+	var err error
+	for attempt := 0; attempt <= source.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		err = source.request(docUrl, cacheFile)
+		var statusErr *HttpStatusError
+		if err == nil || (errors.As(err, &statusErr) && statusErr.StatusCode < http.StatusInternalServerError) {
+			return err
+		}
+	}
+	return err
+}
+
+func (source *HttpSource) request(docUrl string, cacheFile string) error {
+	ctx := context.Background()
+	if source.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, source.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, docUrl, nil)
+	if err != nil {
+		return err
+	}
+	if source.etag != "" && source.content != nil {
+		req.Header.Set("If-None-Match", source.etag)
+	}
+	resp, err := source.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && source.content != nil {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &HttpStatusError{Url: docUrl, StatusCode: resp.StatusCode}
+	}
+	content, err := source.readBody(resp.Body)
+	if err != nil {
+		return err
+	}
+	source.content = content
+	source.etag = resp.Header.Get("ETag")
+	source.contentType = resp.Header.Get("Content-Type")
+	if cacheFile != "" {
+		// Document is received, so cache problem is not a fetch error
+		source.cacheErr = writeFileAtomic(cacheFile, content)
+	}
+	return nil
+}
+
+// Read response body. Body larger than maximal size causes error.
+func (source *HttpSource) readBody(body io.Reader) ([]byte, error) {
+	if source.maxSize <= 0 {
+		return io.ReadAll(body)
+	}
+	content, err := io.ReadAll(io.LimitReader(body, source.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > source.maxSize {
+		return nil, errors.New("document is larger than " + strconv.FormatInt(source.maxSize, 10) + " bytes")
+	}
+	return content, nil
+}
+
+// Returns format of the document. Format is detected by Content-Type
+// header, url extension or content.
+func (source *HttpSource) detectFormat(content []byte) ConfigFormat {
+	source.mutex.Lock()
+	contentType := source.contentType
+	source.mutex.Unlock()
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		format, ok := contentTypeFormats[mediaType]
+		if ok {
+			return format
+		}
+	}
+	docUrl, err := ProcessString(source.url, source.dictionary)
+	if err == nil {
+		parsedUrl, err := url.Parse(docUrl)
+		if err == nil {
+			return DetectFormat(parsedUrl.Path, content)
+		}
+	}
+	return DetectFormat("", content)
+}
+
+// Fetch the document and create configuration processor. Processor
+// process tag of the document format (for example 'yaml' tag for yaml
+// document), 'env' and 'default' tags.
+// Parameters:
+//   - rootPath path to the root of processed structure (see NewFormatTagConverter)
+//
+// Returns:
+//   - configuration processor if success
+//   - error if document can't be fetched or has syntax error
+func (source *HttpSource) Load(rootPath string) (*DynamicTagProcessor, error) {
+	content, err := source.Fetch()
+	if err != nil {
+		return nil, err
+	}
+	format := source.detectFormat(content)
+	if format == FORMAT_UNKNOWN {
+		return nil, errors.New("unknown format of configuration '" + source.url + "'")
+	}
+	converter, err := NewFormatTagConverter(format, content, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(converter)
+	processor.AddTagConverter(NewEnvTagConverter())
+	processor.AddTagConverter(NewDefaultTagConverter())
+	return &processor, nil
+}
+
+// Load configuration document from http(s) server and create configuration
+// processor. See HttpSource for retries, timeouts and cache configuration.
+// Example usage:
+//
+//	processor, err := LoadURL("https://config.local/${SERVICE}.yaml", "$.database")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&databaseConfiguration, nil)
+//
+// Parameters:
+//   - docUrl document url. Can contain placeholders
+//   - rootPath path to the root of processed structure (see NewFormatTagConverter)
+//
+// Returns:
+//   - configuration processor if success
+//   - error if document can't be fetched or has syntax error
+func LoadURL(docUrl string, rootPath string) (*DynamicTagProcessor, error) {
+	return NewHttpSource(docUrl).Load(rootPath)
+}
+
+// Write file via temporary file, so readers never get partially written file.
+func writeFileAtomic(filePath string, content []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filePath)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
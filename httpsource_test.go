@@ -0,0 +1,210 @@
+package dynamictags
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	HTTP_TEST_ETAG = `"v1"`
+	HTTP_TEST_YAML = "database:\n  host: db.remote\n  port: 5432\n"
+)
+
+func newHttpTestServer(t *testing.T, requests *int32, failures *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if atomic.AddInt32(failures, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/prod/config" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("If-None-Match") == HTTP_TEST_ETAG {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", HTTP_TEST_ETAG)
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write([]byte(HTTP_TEST_YAML))
+	}))
+}
+
+func TestHttpSourceFetch(t *testing.T) {
+	var requests, failures int32
+	server := newHttpTestServer(t, &requests, &failures)
+	defer server.Close()
+	cacheFile := filepath.Join(t.TempDir(), "config.cache")
+	source := NewHttpSource(server.URL + "/${HTTP_TEST_ENV}/config")
+	source.SetDictionary(map[string]string{"HTTP_TEST_ENV": "prod"})
+	source.SetRetries(2, time.Millisecond)
+	source.SetCacheFile(cacheFile)
+	// Case 1 document is fetched and cached
+	content, err := source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, HTTP_TEST_YAML, string(content))
+	cached, err := os.ReadFile(cacheFile)
+	assert.NoError(t, err)
+	assert.Equal(t, HTTP_TEST_YAML, string(cached))
+	// Case 2 not modified document
+	content, err = source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, HTTP_TEST_YAML, string(content))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	// Case 3 retries
+	atomic.StoreInt32(&failures, 2)
+	content, err = source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, HTTP_TEST_YAML, string(content))
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+	assert.NoError(t, source.LastError())
+	// Case 4 last known good document from memory
+	atomic.StoreInt32(&failures, 3)
+	content, err = source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, HTTP_TEST_YAML, string(content))
+	assert.Error(t, source.LastError())
+	// Case 5 not found error is not retried and has no fallback
+	atomic.StoreInt32(&requests, 0)
+	source.SetDictionary(map[string]string{"HTTP_TEST_ENV": "dev"})
+	_, err = source.Fetch()
+	statusErr, ok := err.(*HttpStatusError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	// Case 6 last known good document from cache file
+	server.Close()
+	source = NewHttpSource(server.URL + "/prod/config")
+	source.SetCacheFile(cacheFile)
+	content, err = source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, HTTP_TEST_YAML, string(content))
+	assert.Error(t, source.LastError())
+	// Case 7 no cache
+	source = NewHttpSource(server.URL + "/prod/config")
+	_, err = source.Fetch()
+	assert.Error(t, err)
+}
+
+func TestHttpSourceCacheError(t *testing.T) {
+	var requests, failures int32
+	server := newHttpTestServer(t, &requests, &failures)
+	defer server.Close()
+	source := NewHttpSource(server.URL + "/prod/config")
+	source.SetRetries(2, time.Millisecond)
+	source.SetCacheFile(filepath.Join(t.TempDir(), "absent", "config.cache"))
+	// Case 1 cache file can't be written. Document is not retried
+	content, err := source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, HTTP_TEST_YAML, string(content))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.NoError(t, source.LastError())
+	assert.Error(t, source.CacheError())
+	// Case 2 cache file path with placeholders
+	cacheDir := t.TempDir()
+	source = NewHttpSource(server.URL + "/${HTTP_TEST_ENV}/config")
+	source.SetCacheFile(filepath.Join(cacheDir, "${HTTP_TEST_ENV}.cache"))
+	source.SetDictionary(map[string]string{"HTTP_TEST_ENV": "prod"})
+	_, err = source.Fetch()
+	assert.NoError(t, err)
+	assert.NoError(t, source.CacheError())
+	cached, err := os.ReadFile(filepath.Join(cacheDir, "prod.cache"))
+	assert.NoError(t, err)
+	assert.Equal(t, HTTP_TEST_YAML, string(cached))
+}
+
+func TestHttpSourceUrlChange(t *testing.T) {
+	var failing int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&failing) != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		// All documents have the same ETag
+		if r.Header.Get("If-None-Match") == HTTP_TEST_ETAG {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", HTTP_TEST_ETAG)
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+	source := NewHttpSource(server.URL + "/${HTTP_TEST_ENV}")
+	source.SetDictionary(map[string]string{"HTTP_TEST_ENV": "prod"})
+	content, err := source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, "/prod", string(content))
+	// Case 1 ETag of other document is not sent
+	source.SetDictionary(map[string]string{"HTTP_TEST_ENV": "dev"})
+	content, err = source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, "/dev", string(content))
+	// Case 2 last known good document of other url is not used
+	atomic.StoreInt32(&failing, 1)
+	source.SetDictionary(map[string]string{"HTTP_TEST_ENV": "test"})
+	_, err = source.Fetch()
+	assert.Error(t, err)
+}
+
+func TestHttpSourceTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+	// Case 1 request timeout
+	source := NewHttpSource(server.URL)
+	source.SetTimeout(10 * time.Millisecond)
+	_, err := source.Fetch()
+	assert.Error(t, err)
+	// Case 2 shared client is not modified
+	client := &http.Client{}
+	source = NewHttpSource(server.URL)
+	source.SetClient(client)
+	source.SetTimeout(10 * time.Millisecond)
+	_, err = source.Fetch()
+	assert.Error(t, err)
+	assert.Equal(t, time.Duration(0), client.Timeout)
+}
+
+func TestHttpSourceMaxSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+	// Case 1 document size is equal to maximal size
+	source := NewHttpSource(server.URL)
+	source.SetMaxSize(10)
+	content, err := source.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, "0123456789", string(content))
+	// Case 2 document is too large
+	source = NewHttpSource(server.URL)
+	source.SetMaxSize(9)
+	_, err = source.Fetch()
+	assert.Error(t, err)
+}
+
+func TestHttpSourceLoad(t *testing.T) {
+	var requests, failures int32
+	server := newHttpTestServer(t, &requests, &failures)
+	defer server.Close()
+	// Case 1 format by content type
+	processor, err := LoadURL(server.URL+"/prod/config", "$.database")
+	assert.NoError(t, err)
+	data := LoaderTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "db.remote", data.Host)
+	assert.Equal(t, 5432, data.Port)
+	// Case 2 server error
+	_, err = LoadURL(server.URL+"/unknown", "$")
+	assert.Error(t, err)
+}
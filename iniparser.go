@@ -0,0 +1,98 @@
+package dynamictags
+
+import (
+	"strings"
+)
+
+// Parse ini content. Keys of the result are '<section>.<key>' or '<key>' for
+// keys defined before the first section.
+// Supported syntax:
+//   - sections '[section]'. Nested sections can be defined as '[section.subsection]'
+//   - 'key = value' and 'key: value' pairs
+//   - comments. Lines started with ';' or '#'. Inline comments should be
+//     separated from the value by whitespace (like 'key = value ; comment')
+//   - values in double quotes. Quotes are removed, inline comments are not
+//     processed inside quotes
+//   - line continuation. If line ends with '\' the next line is appended to the value
+//
+// Parameters:
+//   - content ini content
+//
+// Returns:
+//   - key value map
+//   - error in case of syntax error (*SyntaxError)
+func ReadIni(content []byte) (map[string]string, error) {
+	res := make(map[string]string)
+	text := string(content)
+	section := ""
+	offset := 0
+	for offset < len(text) {
+		lineStart := offset
+		line, next := readLogicalLine(text, offset)
+		offset = next
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			end := strings.Index(line, "]")
+			if end < 0 {
+				return nil, newSyntaxError(text, lineStart, "']' is expected")
+			}
+			section = strings.TrimSpace(line[1:end])
+			continue
+		}
+		sepIndx := strings.IndexAny(line, "=:")
+		if sepIndx <= 0 {
+			return nil, newSyntaxError(text, lineStart, "'key = value' is expected")
+		}
+		key := strings.TrimSpace(line[:sepIndx])
+		if section != "" {
+			key = section + "." + key
+		}
+		res[key] = parseIniValue(strings.TrimSpace(line[sepIndx+1:]))
+	}
+	return res, nil
+}
+
+// Read line. Lines ended with '\' are joined with the next line.
+// Returns:
+//   - line without continuation characters
+//   - offset of the next line
+func readLogicalLine(text string, offset int) (string, int) {
+	var builder strings.Builder
+	for offset < len(text) {
+		end := strings.IndexByte(text[offset:], '\n')
+		next := len(text)
+		if end >= 0 {
+			next = offset + end + 1
+			end = offset + end
+		} else {
+			end = len(text)
+		}
+		line := strings.TrimRight(text[offset:end], "\r")
+		offset = next
+		if !strings.HasSuffix(line, "\\") {
+			builder.WriteString(line)
+			break
+		}
+		builder.WriteString(line[:len(line)-1])
+	}
+	return builder.String(), offset
+}
+
+func parseIniValue(value string) string {
+	if len(value) >= 2 && value[0] == '"' {
+		end := strings.LastIndexByte(value, '"')
+		if end > 0 {
+			return value[1:end]
+		}
+	}
+	for _, comment := range []string{" ;", " #", "\t;", "\t#"} {
+		indx := strings.Index(value, comment)
+		if indx >= 0 {
+			value = value[:indx]
+		}
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,45 @@
+package dynamictags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const INI_PARSER_DATA = `
+; comment
+# comment
+name = global
+[database]
+host = db.local ; inline comment
+port: 5432
+password = "pass ; word"
+query = select * \
+  from table
+[database.replica]
+host=replica.local
+`
+
+func TestReadIni(t *testing.T) {
+	res, err := ReadIni([]byte(INI_PARSER_DATA))
+	assert.NoError(t, err)
+	assert.Equal(t, "global", res["name"])
+	assert.Equal(t, "db.local", res["database.host"])
+	assert.Equal(t, "5432", res["database.port"])
+	assert.Equal(t, "pass ; word", res["database.password"])
+	assert.Equal(t, "select *   from table", res["database.query"])
+	assert.Equal(t, "replica.local", res["database.replica.host"])
+	assert.Equal(t, 6, len(res))
+}
+
+func TestReadIniErrors(t *testing.T) {
+	// Case 1 no close bracket
+	_, err := ReadIni([]byte("a = 1\n[section"))
+	assert.Error(t, err)
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, syntaxErr.Line)
+	// Case 2 no key value separator
+	_, err = ReadIni([]byte("[section]\nvalue"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package dynamictags
+
+// Create processor to process 'ini' tag.
+// This processor replace structure field with 'ini' tag
+// by value get from ini file. Example usage:
+//
+//	content, err := os.ReadFile("server.ini")
+//	if err != nil {
+//	  return err
+//	}
+//	processor, err := NewIniProcessor(content)
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&serverConfiguration, nil)
+//
+// Returns:
+//   - Ini tag processor if success.
+//   - error if content has syntax error
+func NewIniProcessor(content []byte) (*DynamicTagProcessor, error) {
+	values, err := ReadIni(content)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewIniTagConverter(values))
+	return &processor, nil
+}
@@ -0,0 +1,69 @@
+package dynamictags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_INI_TAG = "ini"
+	INI_PROC_DATA    = `
+[server]
+name = test
+port = 8080
+[database]
+host = db.local
+port = 5432
+`
+)
+
+type IniDatabaseTestStruct struct {
+	Host string `ini:"host"`
+	Port int    `ini:"port"`
+}
+
+type IniTestStruct struct {
+	Name     string                `ini:"server.name"`
+	Port     uint16                `ini:"$.server.port"`
+	Database IniDatabaseTestStruct `ini:"${INI_SECTION}"`
+}
+
+func TestIniTagConverter(t *testing.T) {
+	conv := NewIniTagConverter(map[string]string{"section.key": "value"})
+	assert.NotNil(t, conv)
+	assert.Equal(t, EXPECTED_INI_TAG, conv.GetTag())
+	// Case 1 relative key
+	val, isSet, err := conv.GetSimpleValue("key", reflect.StructField{}, reflect.Value{}, "$.section")
+	assert.Equal(t, "value", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 absolute key
+	val, isSet, err = conv.GetSimpleValue("$.section.key", reflect.StructField{}, reflect.Value{}, "$.other")
+	assert.Equal(t, "value", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 3 no key
+	_, isSet, err = conv.GetSimpleValue("key1", reflect.StructField{}, reflect.Value{}, "$.section")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+}
+
+func TestIniConversion(t *testing.T) {
+	processor, err := NewIniProcessor([]byte(INI_PROC_DATA))
+	assert.NoError(t, err)
+	processor.SetDictionaryValue("INI_SECTION", "database")
+	testStruct := IniTestStruct{}
+	err = processor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", testStruct.Name)
+	assert.Equal(t, uint16(8080), testStruct.Port)
+	assert.Equal(t, "db.local", testStruct.Database.Host)
+	assert.Equal(t, 5432, testStruct.Database.Port)
+
+	// Syntax error
+	processor, err = NewIniProcessor([]byte("[server"))
+	assert.Error(t, err)
+	assert.Nil(t, processor)
+}
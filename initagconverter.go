@@ -0,0 +1,56 @@
+package dynamictags
+
+import "reflect"
+
+const (
+	INI_TAG = "ini"
+)
+
+type IniTagConverter struct {
+	values map[string]string
+}
+
+// Set structure field with 'ini' tag to value from ini file.
+// Tag value is dotted key (like 'ini:"section.key"'). Keys of nested
+// structures are composed in the same way as json paths. I.e. if structure
+// field has tag 'ini:"database"' tag 'ini:"port"' of its field means
+// key 'database.port'. Tag started with '$.' is absolute key.
+// Parameters:
+//   - content parsed ini content (see ReadIni)
+//
+// Returns:
+//   - Ini tag converter.
+func NewIniTagConverter(content map[string]string) TagConverterer {
+	return &IniTagConverter{values: content}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *IniTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	val, ok := conv.values[composeFlatKey(tag, path)]
+	return val, ok, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv IniTagConverter) GetTag() string {
+	return INI_TAG
+}
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv IniTagConverter) HasEncryptedValues() bool {
+	return true
+}
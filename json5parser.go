@@ -0,0 +1,360 @@
+package dynamictags
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Parser of json5 content. Result of parsing is the same as result of json.Unmarshal
+// to 'any' value. I.e. tree of map[string]interface{}, []interface{}, string,
+// float64, bool and nil values.
+type json5Parser struct {
+	content string
+	pos     int
+}
+
+// Parse JSON with comments (JSONC) or JSON5 content. Result can be passed to
+// NewJsonProcessor or NewConfigurationProcessor.
+// In addition to json syntax supported:
+//   - single line ('//') and multi line ('/* */') comments
+//   - trailing commas in objects and arrays
+//   - unquoted object keys (like '{port: 8080}')
+//   - single quoted strings, escaped new lines in strings
+//   - hexadecimal numbers, leading or trailing decimal point, leading '+',
+//     Infinity and NaN
+//
+// Parameters:
+//   - content JSONC or JSON5 content
+//
+// Returns:
+//   - parsed tree
+//   - error in case of syntax error (*SyntaxError)
+func ReadJson5(content []byte) (any, error) {
+	parser := json5Parser{content: string(content)}
+	parser.pos = len(parser.content) - len(strings.TrimPrefix(parser.content, "\uFEFF"))
+	err := parser.skipBlank()
+	if err != nil {
+		return nil, err
+	}
+	res, err := parser.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	err = parser.skipBlank()
+	if err != nil {
+		return nil, err
+	}
+	if !parser.isEnd() {
+		return nil, parser.errorf("unexpected character after value")
+	}
+	return res, nil
+}
+
+func (parser *json5Parser) errorf(msg string) error {
+	return newSyntaxError(parser.content, parser.pos, msg)
+}
+
+func (parser *json5Parser) isEnd() bool {
+	return parser.pos >= len(parser.content)
+}
+
+func (parser *json5Parser) peek() byte {
+	if parser.isEnd() {
+		return 0
+	}
+	return parser.content[parser.pos]
+}
+
+func (parser *json5Parser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(parser.content[parser.pos:], prefix)
+}
+
+// Skip whitespaces and comments.
+func (parser *json5Parser) skipBlank() error {
+	for !parser.isEnd() {
+		if parser.hasPrefix("//") {
+			end := strings.IndexByte(parser.content[parser.pos:], '\n')
+			if end < 0 {
+				parser.pos = len(parser.content)
+				return nil
+			}
+			parser.pos += end + 1
+			continue
+		}
+		if parser.hasPrefix("/*") {
+			end := strings.Index(parser.content[parser.pos+2:], "*/")
+			if end < 0 {
+				return parser.errorf("unterminated comment")
+			}
+			parser.pos += end + 4
+			continue
+		}
+		ch, size := utf8.DecodeRuneInString(parser.content[parser.pos:])
+		if !unicode.IsSpace(ch) && ch != '\uFEFF' {
+			return nil
+		}
+		parser.pos += size
+	}
+	return nil
+}
+
+func (parser *json5Parser) parseValue() (any, error) {
+	switch ch := parser.peek(); {
+	case ch == '{':
+		return parser.parseObject()
+	case ch == '[':
+		return parser.parseArray()
+	case ch == '"' || ch == '\'':
+		return parser.parseString()
+	case parser.hasPrefix("true"):
+		parser.pos += len("true")
+		return true, nil
+	case parser.hasPrefix("false"):
+		parser.pos += len("false")
+		return false, nil
+	case parser.hasPrefix("null"):
+		parser.pos += len("null")
+		return nil, nil
+	case ch == 0:
+		return nil, parser.errorf("value is expected")
+	}
+	return parser.parseNumber()
+}
+
+func (parser *json5Parser) parseObject() (any, error) {
+	parser.pos++
+	res := make(map[string]interface{})
+	for {
+		err := parser.skipBlank()
+		if err != nil {
+			return nil, err
+		}
+		if parser.peek() == '}' {
+			parser.pos++
+			return res, nil
+		}
+		key, err := parser.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		err = parser.skipBlank()
+		if err != nil {
+			return nil, err
+		}
+		if parser.peek() != ':' {
+			return nil, parser.errorf("':' is expected")
+		}
+		parser.pos++
+		err = parser.skipBlank()
+		if err != nil {
+			return nil, err
+		}
+		res[key], err = parser.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		err = parser.skipBlank()
+		if err != nil {
+			return nil, err
+		}
+		switch parser.peek() {
+		case ',':
+			parser.pos++
+		case '}':
+			parser.pos++
+			return res, nil
+		default:
+			return nil, parser.errorf("',' or '}' is expected")
+		}
+	}
+}
+
+func (parser *json5Parser) parseKey() (string, error) {
+	if parser.peek() == '"' || parser.peek() == '\'' {
+		return parser.parseString()
+	}
+	start := parser.pos
+	for !parser.isEnd() {
+		ch, size := utf8.DecodeRuneInString(parser.content[parser.pos:])
+		if ch != '_' && ch != '$' && !unicode.IsLetter(ch) && (parser.pos == start || !unicode.IsDigit(ch)) {
+			break
+		}
+		parser.pos += size
+	}
+	if start == parser.pos {
+		return "", parser.errorf("object key is expected")
+	}
+	return parser.content[start:parser.pos], nil
+}
+
+func (parser *json5Parser) parseArray() (any, error) {
+	parser.pos++
+	res := make([]interface{}, 0)
+	for {
+		err := parser.skipBlank()
+		if err != nil {
+			return nil, err
+		}
+		if parser.peek() == ']' {
+			parser.pos++
+			return res, nil
+		}
+		value, err := parser.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, value)
+		err = parser.skipBlank()
+		if err != nil {
+			return nil, err
+		}
+		switch parser.peek() {
+		case ',':
+			parser.pos++
+		case ']':
+			parser.pos++
+			return res, nil
+		default:
+			return nil, parser.errorf("',' or ']' is expected")
+		}
+	}
+}
+
+func (parser *json5Parser) parseString() (string, error) {
+	quote := parser.peek()
+	parser.pos++
+	var builder strings.Builder
+	for {
+		if parser.isEnd() || parser.peek() == '\n' || parser.peek() == '\r' {
+			return "", parser.errorf("unterminated string")
+		}
+		ch := parser.peek()
+		parser.pos++
+		if ch == quote {
+			return builder.String(), nil
+		}
+		if ch != '\\' {
+			builder.WriteByte(ch)
+			continue
+		}
+		err := parser.parseEscape(&builder)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+func (parser *json5Parser) parseEscape(builder *strings.Builder) error {
+	if parser.isEnd() {
+		return parser.errorf("unterminated string")
+	}
+	ch := parser.peek()
+	parser.pos++
+	switch ch {
+	case 'b':
+		builder.WriteByte('\b')
+	case 'f':
+		builder.WriteByte('\f')
+	case 'n':
+		builder.WriteByte('\n')
+	case 'r':
+		builder.WriteByte('\r')
+	case 't':
+		builder.WriteByte('\t')
+	case 'v':
+		builder.WriteByte('\v')
+	case '0':
+		builder.WriteByte(0)
+	case '\r':
+		// Escaped new line is skipped
+		if parser.peek() == '\n' {
+			parser.pos++
+		}
+	case '\n':
+	case 'x':
+		code, err := parser.parseHex(2)
+		if err != nil {
+			return err
+		}
+		builder.WriteRune(code)
+	case 'u':
+		code, err := parser.parseHex(4)
+		if err != nil {
+			return err
+		}
+		if utf16.IsSurrogate(code) && parser.hasPrefix("\\u") {
+			parser.pos += 2
+			low, err := parser.parseHex(4)
+			if err != nil {
+				return err
+			}
+			code = utf16.DecodeRune(code, low)
+		}
+		builder.WriteRune(code)
+	default:
+		// Other characters (like quotes and backslash) are used as is
+		parser.pos--
+		_, size := utf8.DecodeRuneInString(parser.content[parser.pos:])
+		builder.WriteString(parser.content[parser.pos : parser.pos+size])
+		parser.pos += size
+	}
+	return nil
+}
+
+func (parser *json5Parser) parseHex(size int) (rune, error) {
+	if parser.pos+size > len(parser.content) {
+		return 0, parser.errorf("incorrect escape sequence")
+	}
+	code, err := strconv.ParseUint(parser.content[parser.pos:parser.pos+size], 16, 32)
+	if err != nil {
+		return 0, parser.errorf("incorrect escape sequence")
+	}
+	parser.pos += size
+	return rune(code), nil
+}
+
+func (parser *json5Parser) parseNumber() (any, error) {
+	start := parser.pos
+	sign := 1.0
+	if parser.peek() == '+' || parser.peek() == '-' {
+		if parser.peek() == '-' {
+			sign = -1
+		}
+		parser.pos++
+	}
+	if parser.hasPrefix("Infinity") {
+		parser.pos += len("Infinity")
+		return math.Inf(int(sign)), nil
+	}
+	if parser.hasPrefix("NaN") {
+		parser.pos += len("NaN")
+		return math.NaN(), nil
+	}
+	numStart := parser.pos
+	for !parser.isEnd() && strings.IndexByte("0123456789abcdefABCDEFxX.+-", parser.peek()) >= 0 {
+		// Sign is allowed only after exponent
+		if (parser.peek() == '+' || parser.peek() == '-') && !strings.ContainsAny(parser.content[parser.pos-1:parser.pos], "eE") {
+			break
+		}
+		parser.pos++
+	}
+	token := parser.content[numStart:parser.pos]
+	if strings.HasPrefix(token, "0x") || strings.HasPrefix(token, "0X") {
+		res, err := strconv.ParseUint(token[2:], 16, 64)
+		if err != nil {
+			parser.pos = start
+			return nil, parser.errorf("incorrect number")
+		}
+		return sign * float64(res), nil
+	}
+	res, err := strconv.ParseFloat(token, 64)
+	if token == "" || strings.ContainsAny(token, "xX_") || err != nil {
+		parser.pos = start
+		return nil, parser.errorf("incorrect value")
+	}
+	return sign * res, nil
+}
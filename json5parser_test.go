@@ -0,0 +1,83 @@
+package dynamictags
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const JSON5_DATA = `// Server configuration
+{
+	/* multi
+	   line comment */
+	name: 'server "one"',
+	"quoted": "valueé",
+	$special_key1: true,
+	escaped: 'it\'s \x41 \
+continued',
+	numbers: [0x1F, .5, 5., +7, -1e3, Infinity, -Infinity,],
+	nested: {
+		value: null, // trailing comma
+	},
+}
+`
+
+func TestReadJson5(t *testing.T) {
+	res, err := ReadJson5([]byte(JSON5_DATA))
+	assert.NoError(t, err)
+	tree := res.(map[string]interface{})
+	assert.Equal(t, `server "one"`, tree["name"])
+	assert.Equal(t, "valueé", tree["quoted"])
+	assert.Equal(t, true, tree["$special_key1"])
+	assert.Equal(t, "it's A continued", tree["escaped"])
+	assert.Equal(t, []interface{}{31.0, 0.5, 5.0, 7.0, -1000.0, math.Inf(1), math.Inf(-1)}, tree["numbers"])
+	assert.Equal(t, map[string]interface{}{"value": nil}, tree["nested"])
+	nan, err := ReadJson5([]byte("NaN"))
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(nan.(float64)))
+}
+
+func TestReadJson5Compatibility(t *testing.T) {
+	var expected any
+	err := json.Unmarshal([]byte(JSON_PROC_DATA), &expected)
+	assert.NoError(t, err)
+	res, err := ReadJson5([]byte(JSON_PROC_DATA))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, res)
+}
+
+func TestReadJson5Errors(t *testing.T) {
+	// Case 1 missed comma
+	_, err := ReadJson5([]byte("{\n  a: 1\n  b: 2\n}"))
+	assert.Error(t, err)
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, syntaxErr.Line)
+	assert.Equal(t, 3, syntaxErr.Column)
+	// Case 2 unterminated comment
+	_, err = ReadJson5([]byte("{} /* comment"))
+	assert.Error(t, err)
+	// Case 3 unterminated string
+	_, err = ReadJson5([]byte("{a: 'value\n'}"))
+	assert.Error(t, err)
+	// Case 4 incorrect value
+	_, err = ReadJson5([]byte("[1, undefined]"))
+	assert.Error(t, err)
+	// Case 5 text after value
+	_, err = ReadJson5([]byte("{} {}"))
+	assert.Error(t, err)
+}
+
+func TestJson5Processor(t *testing.T) {
+	res, err := ReadJson5([]byte("{root: {testcfg1: {IntIntData: 5566, IntStrData: 'intstring',},},}"))
+	assert.NoError(t, err)
+	processor, err := NewJsonProcessor(res, TEST_PATH)
+	assert.NoError(t, err)
+	testStruct := IntJsonTestStruct{}
+	err = processor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, EXPECTED_JSON_INT_INT, testStruct.IntData)
+	assert.Equal(t, EXPECTED_JSON_INT_STRING, testStruct.StringData)
+}
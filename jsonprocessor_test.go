@@ -2,7 +2,9 @@ package dynamictags
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -122,3 +124,145 @@ func TestJsonConversion(t *testing.T) {
 	assert.NoError(t, err)
 	jsonProcessorVerifyResult(t, testStruct)
 }
+
+type JsonTreeTestStruct struct {
+	Plugins    map[string]any  `json:"plugins"`
+	RawPlugins json.RawMessage `json:"$.plugins"`
+	Limits     map[string]int  `json:"limits"`
+	AnyString  any             `json:"name"`
+	NoValue    map[string]any  `json:"unknown"`
+}
+
+type JsonAnyTestStruct struct {
+	Plugins any `json:"plugins"`
+}
+
+type JsonStringerTestStruct struct {
+	Name fmt.Stringer `json:"name"`
+}
+
+type JsonErrorTestStruct struct {
+	Plugins error `json:"plugins"`
+}
+
+const JSON_TREE_DATA = `{
+	"name" : "server",
+	"plugins" : {
+		"auth" : {
+			"enabled" : true,
+			"providers" : ["ldap", "oauth"]
+		}
+	},
+	"limits" : {
+		"connections" : 10
+	}
+}
+`
+
+func TestJsonTreeConversion(t *testing.T) {
+	var res any
+	err := json.Unmarshal([]byte(JSON_TREE_DATA), &res)
+	assert.NoError(t, err)
+	jsonProcessor, err := NewJsonProcessor(res, "$")
+	assert.NoError(t, err)
+	// Case 1 tree values
+	testStruct := JsonTreeTestStruct{}
+	err = jsonProcessor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	expected := map[string]any{
+		"auth": map[string]any{
+			"enabled":   true,
+			"providers": []any{"ldap", "oauth"},
+		},
+	}
+	assert.Equal(t, expected, testStruct.Plugins)
+	assert.JSONEq(t, `{"auth":{"enabled":true,"providers":["ldap","oauth"]}}`, string(testStruct.RawPlugins))
+	assert.Equal(t, map[string]int{"connections": 10}, testStruct.Limits)
+	assert.Equal(t, "server", testStruct.AnyString)
+	assert.Nil(t, testStruct.NoValue)
+	anyStruct := JsonAnyTestStruct{}
+	err = jsonProcessor.Process(&anyStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, anyStruct.Plugins)
+	// Case 2 value is not assignable to interface
+	err = jsonProcessor.Process(&JsonStringerTestStruct{}, nil)
+	assert.Error(t, err)
+	err = jsonProcessor.Process(&JsonErrorTestStruct{}, nil)
+	assert.Error(t, err)
+}
+
+type JsonSliceTestServer struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+type JsonSliceTestStruct struct {
+	Servers []JsonSliceTestServer `json:"servers"`
+	Ports   []int                 `json:"ports"`
+	Started time.Time             `json:"started"`
+}
+
+type JsonSliceDefaultTestStruct struct {
+	Items   []JsonSliceTestServer `json:"items" default:"[]"`
+	Servers []JsonSliceTestServer `json:"servers" default:"[{\"name\": \"local\", \"port\": 80}]"`
+}
+
+type JsonSliceUntaggedTestStruct struct {
+	Servers []JsonSliceTestServer
+}
+
+const JSON_SLICE_DATA = `{
+	"servers" : [
+		{"name" : "alpha", "port" : 8080},
+		{"name" : "beta", "port" : 8081}
+	],
+	"ports" : [80, 443],
+	"started" : "2024-05-01T10:00:00Z"
+}
+`
+
+func TestJsonSliceConversion(t *testing.T) {
+	var res any
+	err := json.Unmarshal([]byte(JSON_SLICE_DATA), &res)
+	assert.NoError(t, err)
+	jsonProcessor, err := NewJsonProcessor(res, "$")
+	assert.NoError(t, err)
+	// Case 1 slice of structures, typed slice and time
+	testStruct := JsonSliceTestStruct{}
+	err = jsonProcessor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	expected := JsonSliceTestStruct{
+		Servers: []JsonSliceTestServer{{Name: "alpha", Port: 8080}, {Name: "beta", Port: 8081}},
+		Ports:   []int{80, 443},
+		Started: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, expected, testStruct)
+	// Case 2 slice of structures is not an array
+	err = json.Unmarshal([]byte(`{"servers" : "alpha"}`), &res)
+	assert.NoError(t, err)
+	jsonProcessor, err = NewJsonProcessor(res, "$")
+	assert.NoError(t, err)
+	err = jsonProcessor.Process(&JsonSliceTestStruct{}, nil)
+	assert.Error(t, err)
+	// Case 3 default value in json format
+	err = json.Unmarshal([]byte(`{"ports" : [80]}`), &res)
+	assert.NoError(t, err)
+	jsonProcessor, err = NewJsonProcessor(res, "$")
+	assert.NoError(t, err)
+	jsonProcessor.AddTagConverter(NewDefaultTagConverter())
+	defaultStruct := JsonSliceDefaultTestStruct{}
+	err = jsonProcessor.Process(&defaultStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []JsonSliceTestServer{}, defaultStruct.Items)
+	assert.Equal(t, []JsonSliceTestServer{{Name: "local", Port: 80}}, defaultStruct.Servers)
+	// Case 4 field without tags is looked up by field name
+	err = json.Unmarshal([]byte(`{"Servers" : [{"name" : "alpha", "port" : 8080}, {"name" : "beta", "port" : 8081}]}`), &res)
+	assert.NoError(t, err)
+	jsonProcessor, err = NewJsonProcessor(res, "$")
+	assert.NoError(t, err)
+	jsonProcessor.AddTagConverter(NewDefaultTagConverter())
+	untaggedStruct := JsonSliceUntaggedTestStruct{}
+	err = jsonProcessor.Process(&untaggedStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []JsonSliceTestServer{{Name: "alpha", Port: 8080}, {Name: "beta", Port: 8081}}, untaggedStruct.Servers)
+}
@@ -2,7 +2,6 @@ package dynamictags
 
 import (
 	"reflect"
-	"strings"
 
 	"github.com/PaesslerAG/jsonpath"
 )
@@ -13,12 +12,15 @@ const (
 
 type JsonTagConverter struct {
 	jsonData any
+	// Whole document and root path. Used to apply profile overlay
+	tree     any
+	rootPath string
 }
 
 func NewJsonTagConverter(content any, rootPath string) (TagConverterer, error) {
-	conv := JsonTagConverter{}
+	conv := JsonTagConverter{tree: content, rootPath: rootPath}
 	var err error
-	conv.jsonData, err = jsonpath.Get(rootPath, content)
+	conv.jsonData, err = jsonpath.Get(rootPath, conv.tree)
 	if err != nil {
 		return nil, err
 	}
@@ -26,15 +28,34 @@ func NewJsonTagConverter(content any, rootPath string) (TagConverterer, error) {
 }
 
 func (conv *JsonTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
-	jsonPath := tag
-	if !strings.HasPrefix(tag, "$") {
-		// If path is relative
-		jsonPath = path + "." + tag
-	}
-	data, err := jsonpath.Get(jsonPath, conv.jsonData)
-	return data, err == nil, nil
+	data, ok := getTreeValue(conv.jsonData, tag, path)
+	return data, ok, nil
 }
 
 func (conv JsonTagConverter) GetTag() string {
 	return JSON_TAG
 }
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv JsonTagConverter) HasEncryptedValues() bool {
+	return true
+}
+
+// Returns converter for the profile. If document has overlay for the
+// profile (see ApplyProfile) new converter for the overlaid document is
+// returned.
+// Parameters:
+//   - profile profile name
+//
+// Returns:
+//   - converter for the profile
+//   - error if root path is not found in the overlaid document
+func (conv *JsonTagConverter) WithProfile(profile string) (TagConverterer, error) {
+	tree, ok := applyProfile(conv.tree, profile)
+	if !ok {
+		return conv, nil
+	}
+	return NewJsonTagConverter(tree, conv.rootPath)
+}
@@ -0,0 +1,185 @@
+package dynamictags
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Interface of key/value store. Keys are '/' separated paths (like
+// 'config/myapp/database/port'). Store can be used as configuration
+// source by NewKVStoreTagConverter.
+type KVStore interface {
+	// Returns value of the key.
+	// Parameters:
+	//   - ctx context
+	//   - key key
+	//
+	// Returns:
+	//   - value
+	//   - false if key doesn't exist
+	//   - error in case of error
+	Get(ctx context.Context, key string) (string, bool, error)
+
+	// Returns all values under the prefix.
+	// Parameters:
+	//   - ctx context
+	//   - prefix keys prefix (like 'config/myapp'). Empty prefix means all keys
+	//
+	// Returns:
+	//   - values. Keys are full keys (with prefix)
+	//   - error in case of error
+	List(ctx context.Context, prefix string) (map[string]string, error)
+
+	// Watch changes of values under the prefix.
+	// Parameters:
+	//   - ctx context. Watching is stopped when context is done
+	//   - prefix keys prefix
+	//
+	// Returns:
+	//   - channel which receives notification when values under the prefix
+	//     are changed. Channel is closed when watching is stopped
+	//   - error in case of error
+	Watch(ctx context.Context, prefix string) (<-chan struct{}, error)
+}
+
+// In memory key/value store. Can be used in tests.
+type MemoryKVStore struct {
+	mutex    sync.Mutex
+	values   map[string]string
+	watchers map[*kvWatcher]struct{}
+}
+
+type kvWatcher struct {
+	prefix string
+	notify chan struct{}
+}
+
+// Create in memory key/value store.
+// Parameters:
+//   - values initial values. Can be nil
+//
+// Returns:
+//   - key/value store
+func NewMemoryKVStore(values map[string]string) *MemoryKVStore {
+	store := MemoryKVStore{
+		values:   make(map[string]string, len(values)),
+		watchers: make(map[*kvWatcher]struct{}),
+	}
+	for key, val := range values {
+		store.values[key] = val
+	}
+	return &store
+}
+
+// Returns value of the key.
+// Parameters:
+//   - ctx context
+//   - key key
+//
+// Returns:
+//   - value
+//   - false if key doesn't exist
+//   - error in case of error
+func (store *MemoryKVStore) Get(ctx context.Context, key string) (string, bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	val, ok := store.values[key]
+	return val, ok, nil
+}
+
+// Returns all values under the prefix.
+// Parameters:
+//   - ctx context
+//   - prefix keys prefix
+//
+// Returns:
+//   - values. Keys are full keys
+//   - error in case of error
+func (store *MemoryKVStore) List(ctx context.Context, prefix string) (map[string]string, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	res := make(map[string]string)
+	for key, val := range store.values {
+		if isKVKeyUnder(key, prefix) {
+			res[key] = val
+		}
+	}
+	return res, nil
+}
+
+// Watch changes of values under the prefix.
+// Parameters:
+//   - ctx context
+//   - prefix keys prefix
+//
+// Returns:
+//   - notifications channel
+//   - error in case of error
+func (store *MemoryKVStore) Watch(ctx context.Context, prefix string) (<-chan struct{}, error) {
+	watcher := &kvWatcher{prefix: prefix, notify: make(chan struct{}, 1)}
+	store.mutex.Lock()
+	store.watchers[watcher] = struct{}{}
+	store.mutex.Unlock()
+	go func() {
+		<-ctx.Done()
+		store.mutex.Lock()
+		defer store.mutex.Unlock()
+		delete(store.watchers, watcher)
+		close(watcher.notify)
+	}()
+	return watcher.notify, nil
+}
+
+// Set value of the key. Watchers of the key are notified.
+// Parameters:
+//   - key key
+//   - value value
+func (store *MemoryKVStore) Set(key string, value string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.values[key] = value
+	store.notify(key)
+}
+
+// Delete the key. Watchers of the key are notified.
+// Parameters:
+//   - key key
+func (store *MemoryKVStore) Delete(key string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	_, ok := store.values[key]
+	if !ok {
+		return
+	}
+	delete(store.values, key)
+	store.notify(key)
+}
+
+func (store *MemoryKVStore) notify(key string) {
+	for watcher := range store.watchers {
+		if !isKVKeyUnder(key, watcher.prefix) {
+			continue
+		}
+		// Notification is skipped if previous one is not received yet
+		select {
+		case watcher.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Returns true if key is the prefix or is under the prefix.
+func isKVKeyUnder(key string, prefix string) bool {
+	prefix = strings.Trim(prefix, "/")
+	return prefix == "" || key == prefix || strings.HasPrefix(key, prefix+"/")
+}
+
+// Returns key composed from prefix and relative key.
+func joinKVKey(prefix string, key string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
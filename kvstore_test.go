@@ -0,0 +1,76 @@
+package dynamictags
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryKVStore(t *testing.T) {
+	store := NewMemoryKVStore(map[string]string{"app/name": "app", "app/database/port": "5432", "application/name": "other"})
+	ctx := context.Background()
+	// Case 1 get
+	val, ok, err := store.Get(ctx, "app/name")
+	assert.Equal(t, "app", val)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	_, ok, err = store.Get(ctx, "app/unknown")
+	assert.False(t, ok)
+	assert.NoError(t, err)
+	// Case 2 list
+	values, err := store.List(ctx, "app/")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app/name": "app", "app/database/port": "5432"}, values)
+	values, err = store.List(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, values, 3)
+	// Case 3 watch
+	watchCtx, cancel := context.WithCancel(ctx)
+	notify, err := store.Watch(watchCtx, "app")
+	assert.NoError(t, err)
+	store.Set("application/name", "changed")
+	select {
+	case <-notify:
+		assert.Fail(t, "unexpected notification")
+	default:
+	}
+	store.Set("app/name", "changed")
+	_, ok = <-notify
+	assert.True(t, ok)
+	store.Delete("app/name")
+	_, ok = <-notify
+	assert.True(t, ok)
+	_, ok, _ = store.Get(ctx, "app/name")
+	assert.False(t, ok)
+	// Case 4 channel is closed when context is cancelled
+	cancel()
+	select {
+	case _, ok = <-notify:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "channel is not closed")
+	}
+}
+
+func TestKVStoreConverter(t *testing.T) {
+	store := NewMemoryKVStore(map[string]string{"config/app/database/port": "5432", "config/app/name": "app"})
+	conv := NewKVStoreTagConverter(store, "config/app")
+	assert.Equal(t, EXPECTED_KV_TAG, conv.GetTag())
+	// Case 1 nested key
+	val, isSet, err := conv.GetSimpleValue("port", reflect.StructField{}, reflect.Value{}, "$.database")
+	assert.Equal(t, "5432", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 value is get from store on every call
+	store.Set("config/app/name", "changed")
+	val, isSet, _ = conv.GetSimpleValue("name", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, "changed", val)
+	assert.True(t, isSet)
+	// Case 3 unknown key
+	_, isSet, err = conv.GetSimpleValue("unknown", reflect.StructField{}, reflect.Value{}, "$")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,111 @@
+package dynamictags
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+const (
+	KV_TAG = "kv"
+)
+
+type KvTagConverter struct {
+	store  KVStore
+	prefix string
+}
+
+// Set structure field with 'kv' tag to value from key/value map. Tag
+// value is key. Keys of nested structures are composed in the same way as
+// json paths but parts are separated by '/'. I.e. if structure field has
+// tag 'kv:"database"' tag 'kv:"port"' of its field means key
+// 'database/port'. Tags are used as written, so keys can contain '.'. Tag
+// started with '$.' is absolute key (like 'kv:"$.database/port"'). Example
+// usage:
+//
+//	client := NewConsulClient("")
+//	values, index, err := client.List("config/myapp")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.AddTagConverter(NewKvTagConverter(values))
+//
+// Parameters:
+//   - values key/value store values (see ConsulClient.List)
+//
+// Returns:
+//   - Key/value tag converter.
+func NewKvTagConverter(values map[string]string) TagConverterer {
+	return &KvTagConverter{store: NewMemoryKVStore(values)}
+}
+
+// Set structure field with 'kv' tag to value from key/value store. Keys are
+// composed in the same way as in NewKvTagConverter and are relative to the
+// prefix. Example usage:
+//
+//	store := NewDirKVStore("/etc/myapp")
+//	processor.AddTagConverter(NewKVStoreTagConverter(store, "prod"))
+//
+// Parameters:
+//   - store key/value store (like MemoryKVStore, DirKVStore or ConsulKVStore)
+//   - prefix keys prefix (like 'config/myapp')
+//
+// Returns:
+//   - Key/value tag converter.
+func NewKVStoreTagConverter(store KVStore, prefix string) TagConverterer {
+	return &KvTagConverter{store: store, prefix: prefix}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *KvTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	val, ok, err := conv.store.Get(context.Background(), joinKVKey(conv.prefix, composeKvKey(tag, path)))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv KvTagConverter) GetTag() string {
+	return KV_TAG
+}
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv KvTagConverter) HasEncryptedValues() bool {
+	return true
+}
+
+// Returns path to the nested structure. Parts of path are separated by '/'.
+// Parameters:
+//   - path path to the parent structure
+//   - tag processed tag of the nested structure
+//
+// Returns:
+//   - path to the nested structure
+func (conv KvTagConverter) ComposeTagPath(path string, tag string) string {
+	return path + "/" + tag
+}
+
+func composeKvKey(tag string, path string) string {
+	if !strings.HasPrefix(tag, "$") {
+		tag = path + "/" + tag
+	}
+	key := strings.TrimPrefix(tag, "$")
+	key = strings.TrimPrefix(key, ".")
+	return strings.TrimPrefix(key, "/")
+}
@@ -0,0 +1,67 @@
+package dynamictags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_KV_TAG = "kv"
+)
+
+func TestKvConverter(t *testing.T) {
+	conv := NewKvTagConverter(map[string]string{"database/port": "5432", "name": "app"})
+	assert.Equal(t, EXPECTED_KV_TAG, conv.GetTag())
+	// Case 1 nested key
+	val, isSet, err := conv.GetSimpleValue("port", reflect.StructField{}, reflect.Value{}, "$.database")
+	assert.Equal(t, "5432", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 absolute key
+	val, isSet, _ = conv.GetSimpleValue("$.name", reflect.StructField{}, reflect.Value{}, "$.database")
+	assert.Equal(t, "app", val)
+	assert.True(t, isSet)
+	// Case 3 key with '/'
+	val, isSet, _ = conv.GetSimpleValue("database/port", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, "5432", val)
+	assert.True(t, isSet)
+	// Case 4 unknown key
+	_, isSet, err = conv.GetSimpleValue("unknown", reflect.StructField{}, reflect.Value{}, "$")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+	// Case 5 nested path
+	composer := conv.(TagPathComposer)
+	val, isSet, _ = conv.GetSimpleValue("port", reflect.StructField{}, reflect.Value{}, composer.ComposeTagPath("$", "database"))
+	assert.Equal(t, "5432", val)
+	assert.True(t, isSet)
+}
+
+type KvDottedTestStruct struct {
+	Tenant struct {
+		Host string `kv:"db.host"`
+		Port int    `kv:"db/port"`
+	} `kv:"${KV_TEST_TENANT}"`
+	Version string `kv:"$.app.version"`
+}
+
+func TestKvProcessorDottedKeys(t *testing.T) {
+	values := map[string]string{
+		"acme.corp/db.host": "db.acme",
+		"acme.corp/db/port": "5433",
+		"app.version":       "1.2",
+		"acme/corp/db/port": "1",
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewKvTagConverter(values))
+	processor.SetDictionaryValue("KV_TEST_TENANT", "acme.corp")
+	// Case 1 dots in tags and placeholder values are not separators
+	data := KvDottedTestStruct{}
+	err := processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "db.acme", data.Tenant.Host)
+	assert.Equal(t, 5433, data.Tenant.Port)
+	assert.Equal(t, "1.2", data.Version)
+}
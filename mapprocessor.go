@@ -0,0 +1,24 @@
+package dynamictags
+
+// Create processor to process specified tag and 'default' tag. Values are
+// get from map (see NewMapTagConverter). Example usage:
+//
+//	processor := NewMapProcessor("fixture", map[string]any{
+//	  "database.host": "localhost",
+//	  "database": map[string]any{"port": 5432},
+//	})
+//	processor.Process(&configuration, nil)
+//
+// Parameters:
+//   - tag processed tag
+//   - values values
+//
+// Returns:
+//   - Map tag processor.
+func NewMapProcessor(tag string, values map[string]any) *DynamicTagProcessor {
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewMapTagConverter(tag, values))
+	processor.AddTagConverter(NewDefaultTagConverter())
+	return &processor
+}
@@ -0,0 +1,160 @@
+package dynamictags
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type MapTagConverter struct {
+	tag    string
+	values map[string]any
+}
+
+// Set structure field with specified tag to value from map. Tag value is
+// key. Keys of nested structures are composed in the same way as json
+// paths. I.e. if structure field has tag 'fixture:"database"' tag
+// 'fixture:"port"' of its field means key 'database.port'. Key can be
+// dotted key of the map or path in nested maps, i.e. both
+// {"database.port": 5432} and {"database": {"port": 5432}} are found.
+// Tag started with '$.' is absolute key. Numbers and booleans are converted
+// to string for string fields. Other values which can't be converted to the
+// field type cause error. Example usage:
+//
+//	values := map[string]any{"database": map[string]any{"port": 5432}}
+//	processor.AddTagConverter(NewMapTagConverter("fixture", values))
+//
+// Parameters:
+//   - tag processed tag
+//   - values values. Values can be simple values, slices or nested maps
+//
+// Returns:
+//   - Map tag converter.
+func NewMapTagConverter(tag string, values map[string]any) TagConverterer {
+	return &MapTagConverter{tag: tag, values: values}
+}
+
+// Set structure field with specified tag to value from string map (see
+// NewMapTagConverter).
+// Parameters:
+//   - tag processed tag
+//   - values values
+//
+// Returns:
+//   - Map tag converter.
+func NewStringMapTagConverter(tag string, values map[string]string) TagConverterer {
+	anyValues := make(map[string]any, len(values))
+	for key, val := range values {
+		anyValues[key] = val
+	}
+	return &MapTagConverter{tag: tag, values: anyValues}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *MapTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	val, ok := lookupMapValue(conv.values, composeFlatKey(tag, path))
+	if !ok || val == nil {
+		return nil, false, nil
+	}
+	if t.Type != nil && t.Type.Kind() == reflect.String {
+		strVal, err := convertMapString(val)
+		if err != nil {
+			return nil, false, errors.New(err.Error() + ". Path: " + path + "." + t.Name)
+		}
+		return strVal, true, nil
+	}
+	val = normalizeMapValue(val)
+	if t.Type != nil && t.Type.Kind() == reflect.Slice && t.Type != rawMessageType {
+		switch val.(type) {
+		case []interface{}, string:
+		default:
+			return nil, false, errors.New("slice value is expected. Path: " + path + "." + t.Name)
+		}
+	}
+	return val, true, nil
+}
+
+// Convert simple value to string.
+func convertMapString(val any) (string, error) {
+	switch reflect.ValueOf(val).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(val), nil
+	}
+	return "", errors.New("value of type " + reflect.TypeOf(val).String() + " can't be converted to string")
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv MapTagConverter) GetTag() string {
+	return conv.tag
+}
+
+// Find value by dotted key. Each part of the key can be a key of nested
+// map or a part of dotted key.
+func lookupMapValue(node any, key string) (any, bool) {
+	switch values := node.(type) {
+	case map[string]any:
+		val, ok := values[key]
+		if ok {
+			return val, true
+		}
+		for indx := strings.IndexByte(key, '.'); indx >= 0; {
+			child, ok := values[key[:indx]]
+			if ok {
+				val, ok = lookupMapValue(child, key[indx+1:])
+				if ok {
+					return val, true
+				}
+			}
+			next := strings.IndexByte(key[indx+1:], '.')
+			if next < 0 {
+				break
+			}
+			indx += next + 1
+		}
+	case map[string]string:
+		val, ok := values[key]
+		return val, ok
+	}
+	return nil, false
+}
+
+// Convert typed slices and maps to values of json tree, so they can be
+// processed in the same way as values of other sources.
+func normalizeMapValue(val any) any {
+	switch typedVal := val.(type) {
+	case []interface{}, map[string]interface{}:
+		return val
+	case map[string]string:
+		res := make(map[string]interface{}, len(typedVal))
+		for key, item := range typedVal {
+			res[key] = item
+		}
+		return res
+	}
+	refVal := reflect.ValueOf(val)
+	if refVal.Kind() != reflect.Slice || refVal.Type() == rawMessageType || refVal.Type().Elem().Kind() == reflect.Uint8 {
+		return val
+	}
+	res := make([]interface{}, refVal.Len())
+	for i := range res {
+		res[i] = refVal.Index(i).Interface()
+	}
+	return res
+}
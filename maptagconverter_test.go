@@ -0,0 +1,135 @@
+package dynamictags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	MAP_TEST_TAG = "fixture"
+)
+
+type MapTestDatabase struct {
+	Host    string         `fixture:"host"`
+	Port    int            `fixture:"port"`
+	Options map[string]any `fixture:"options"`
+}
+
+type MapTestStruct struct {
+	Name     string          `fixture:"name"`
+	Debug    bool            `fixture:"debug" default:"true"`
+	Tags     []string        `fixture:"tags"`
+	Ports    []int           `fixture:"ports"`
+	Database MapTestDatabase `fixture:"database"`
+	Version  string          `fixture:"$.version"`
+	Ratio    float64         `fixture:"ratio" default:"0.5"`
+}
+
+func TestMapTagConverter(t *testing.T) {
+	values := map[string]any{
+		"name":          "service",
+		"tags":          []string{"a", "b"},
+		"ports":         []int{80, 443},
+		"database.host": "localhost",
+		"database": map[string]any{
+			"port":    5432,
+			"options": map[string]string{"sslmode": "require"},
+		},
+		"version": "1.0",
+		"ratio":   nil,
+	}
+	conv := NewMapTagConverter(MAP_TEST_TAG, values)
+	assert.Equal(t, MAP_TEST_TAG, conv.GetTag())
+	// Case 1. Dotted key
+	data := MapTestStruct{}
+	val, ok, err := conv.GetSimpleValue("host", reflect.StructField{}, reflect.ValueOf(&data).Elem(), "$.database")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "localhost", val)
+	// Case 2. Nested maps
+	val, ok, err = conv.GetSimpleValue("port", reflect.StructField{}, reflect.ValueOf(&data).Elem(), "$.database")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 5432, val)
+	// Case 3. Absent and nil values are not set
+	_, ok, err = conv.GetSimpleValue("absent", reflect.StructField{}, reflect.ValueOf(&data).Elem(), "$.database")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	_, ok, err = conv.GetSimpleValue("ratio", reflect.StructField{}, reflect.ValueOf(&data).Elem(), "$")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	// Case 4. Processing
+	processor := NewMapProcessor(MAP_TEST_TAG, values)
+	err = processor.Process(&data, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, MapTestStruct{
+		Name:  "service",
+		Debug: true,
+		Tags:  []string{"a", "b"},
+		Ports: []int{80, 443},
+		Database: MapTestDatabase{
+			Host:    "localhost",
+			Port:    5432,
+			Options: map[string]any{"sslmode": "require"},
+		},
+		Version: "1.0",
+		Ratio:   0.5,
+	}, data)
+}
+
+func TestStringMapTagConverter(t *testing.T) {
+	values := map[string]string{
+		"name":          "service",
+		"debug":         "false",
+		"tags":          "a,b",
+		"database.host": "localhost",
+		"database.port": "5432",
+		"ratio":         "0.25",
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewStringMapTagConverter(MAP_TEST_TAG, values))
+	data := MapTestStruct{}
+	// Case 1. Correct values
+	err := processor.Process(&data, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, MapTestStruct{
+		Name:     "service",
+		Tags:     []string{"a", "b"},
+		Database: MapTestDatabase{Host: "localhost", Port: 5432},
+		Ratio:    0.25,
+	}, data)
+	// Case 2. Incorrect value
+	values["ratio"] = "ratio"
+	processor = DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewStringMapTagConverter(MAP_TEST_TAG, values))
+	err = processor.Process(&data, nil)
+	assert.NotNil(t, err)
+}
+
+func TestMapTagConverterStringConversion(t *testing.T) {
+	var data struct {
+		Code    string `fixture:"code"`
+		Enabled string `fixture:"enabled"`
+		Ratio   string `fixture:"ratio"`
+		Items   string `fixture:"items"`
+		Ports   []int  `fixture:"ports"`
+	}
+	// Case 1. Numbers and booleans are converted to string
+	processor := NewMapProcessor(MAP_TEST_TAG, map[string]any{"code": 7, "enabled": true, "ratio": 0.5})
+	err := processor.Process(&data, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "7", data.Code)
+	assert.Equal(t, "true", data.Enabled)
+	assert.Equal(t, "0.5", data.Ratio)
+	// Case 2. Value can't be converted
+	processor = NewMapProcessor(MAP_TEST_TAG, map[string]any{"items": []int{1, 2}})
+	err = processor.Process(&data, nil)
+	assert.NotNil(t, err)
+	processor = NewMapProcessor(MAP_TEST_TAG, map[string]any{"ports": 80})
+	err = processor.Process(&data, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,436 @@
+package dynamictags
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Configuration tree merged from several documents. The tree remembers
+// which document each leaf value came from. Arrays are leaves, i.e. array
+// has one source document.
+// Example usage:
+//
+//	merged := NewMergedTree()
+//	merged.Merge("base.json", base)
+//	merged.Merge("region.json", region)
+//	merged.Merge("host.json", host)
+//	processor, err := NewJsonProcessor(merged.GetTree(), "$")
+type MergedTree struct {
+	tree any
+	// Json path of leaf -> document name
+	sources map[string]string
+}
+
+// Create empty merged tree.
+// Returns:
+//   - merged tree
+func NewMergedTree() *MergedTree {
+	return &MergedTree{sources: make(map[string]string)}
+}
+
+// Apply RFC 7396 merge patch to the target. Target is not modified.
+// Parameters:
+//   - target target tree
+//   - patch merge patch
+//
+// Returns:
+//   - patched tree
+func MergePatch(target any, patch any) any {
+	merged := NewMergedTree()
+	merged.tree = target
+	merged.Merge("", patch)
+	return merged.tree
+}
+
+// Returns merged tree. The tree can be passed to NewJsonTagConverter.
+// Returns:
+//   - merged tree
+func (merged *MergedTree) GetTree() any {
+	return merged.tree
+}
+
+// Returns name of document the value came from.
+// Parameters:
+//   - path json path to the value (like '$.database.port'). For array
+//     elements and values inside of arrays source of the array is returned
+//
+// Returns:
+//   - document name
+//   - true if value is found
+func (merged *MergedTree) GetSource(path string) (string, bool) {
+	indx := strings.Index(path, "[")
+	if indx >= 0 {
+		path = path[:indx]
+	}
+	source, ok := merged.sources[path]
+	return source, ok
+}
+
+// Merge document into the tree with RFC 7396 merge patch semantic. Objects
+// are merged recursively, null values remove keys, other values replace
+// existed values.
+// Parameters:
+//   - name document name (for example file name)
+//   - doc document tree (like result of json.Unmarshal)
+func (merged *MergedTree) Merge(name string, doc any) {
+	merged.tree = merged.mergeValue(merged.tree, doc, "$", name)
+}
+
+func (merged *MergedTree) mergeValue(target any, patch any, path string, name string) any {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		merged.removeSources(path)
+		merged.addSources(path, patch, name)
+		return patch
+	}
+	targetMap, ok := target.(map[string]interface{})
+	res := make(map[string]interface{}, len(targetMap)+len(patchMap))
+	if ok {
+		for key, val := range targetMap {
+			res[key] = val
+		}
+	} else {
+		merged.removeSources(path)
+	}
+	for key, val := range patchMap {
+		childPath := path + "." + key
+		if val == nil {
+			delete(res, key)
+			merged.removeSources(childPath)
+			continue
+		}
+		res[key] = merged.mergeValue(res[key], val, childPath, name)
+	}
+	return res
+}
+
+// Apply RFC 6902 JSON patch to the tree. Operations are applied atomically,
+// i.e. if one operation fails the tree is not changed.
+// Parameters:
+//   - name patch name. Values set by the patch get this source
+//   - patch list of operations (like result of json.Unmarshal of json patch document)
+//
+// Returns:
+//   - error if patch is incorrect or operation fails
+func (merged *MergedTree) ApplyPatch(name string, patch any) error {
+	ops, ok := patch.([]interface{})
+	if !ok {
+		return errors.New("json patch should be an array of operations")
+	}
+	res := &MergedTree{
+		tree:    deepCopyTree(merged.tree),
+		sources: make(map[string]string, len(merged.sources)),
+	}
+	for key, val := range merged.sources {
+		res.sources[key] = val
+	}
+	for i, op := range ops {
+		opMap, ok := op.(map[string]interface{})
+		if !ok {
+			return errors.New("json patch operation " + strconv.Itoa(i) + " should be an object")
+		}
+		err := res.applyOperation(name, opMap)
+		if err != nil {
+			return errors.New("json patch operation " + strconv.Itoa(i) + ": " + err.Error())
+		}
+	}
+	merged.tree = res.tree
+	merged.sources = res.sources
+	return nil
+}
+
+func (merged *MergedTree) applyOperation(name string, op map[string]interface{}) error {
+	opName, _ := op["op"].(string)
+	pathStr, ok := op["path"].(string)
+	if !ok {
+		return errors.New("'path' is expected")
+	}
+	path, err := parseJsonPointer(pathStr)
+	if err != nil {
+		return err
+	}
+	value, hasValue := op["value"]
+	switch opName {
+	case "add", "replace":
+		if !hasValue {
+			return errors.New("'value' is expected")
+		}
+		if opName == "replace" {
+			_, err = getByPointer(merged.tree, path)
+			if err != nil {
+				return err
+			}
+		}
+		err = merged.setByPointer(name, path, deepCopyTree(value), opName == "replace")
+	case "remove":
+		err = merged.removeByPointer(path)
+	case "move", "copy":
+		fromStr, ok := op["from"].(string)
+		if !ok {
+			return errors.New("'from' is expected")
+		}
+		from, err := parseJsonPointer(fromStr)
+		if err != nil {
+			return err
+		}
+		value, err = getByPointer(merged.tree, from)
+		if err != nil {
+			return err
+		}
+		value = deepCopyTree(value)
+		if opName == "move" {
+			if strings.HasPrefix(pathStr+"/", fromStr+"/") && pathStr != fromStr {
+				return errors.New("value can't be moved into its child")
+			}
+			err = merged.removeByPointer(from)
+			if err != nil {
+				return err
+			}
+		}
+		return merged.setByPointer(name, path, value, false)
+	case "test":
+		actual, err := getByPointer(merged.tree, path)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(actual, value) {
+			return errors.New("test failed for path '" + pathStr + "'")
+		}
+	default:
+		return errors.New("unsupported operation '" + opName + "'")
+	}
+	return err
+}
+
+func (merged *MergedTree) setByPointer(name string, path []string, value any, replace bool) error {
+	var err error
+	if len(path) == 0 {
+		merged.tree = value
+	} else {
+		merged.tree, err = modifyByPointer(merged.tree, path, func(parent any, key string) (any, error) {
+			return setChild(parent, key, value, replace)
+		})
+	}
+	if err != nil {
+		return err
+	}
+	sourcePath := merged.getSourcePath(path)
+	sourceValue, _ := getByPointer(merged.tree, path)
+	if sourcePath != pointerToJsonPath(path) {
+		// Value is inside of array. Array is a leaf
+		sourceValue, _ = getByJsonPath(merged.tree, sourcePath)
+	}
+	merged.removeSources(sourcePath)
+	merged.addSources(sourcePath, sourceValue, name)
+	return nil
+}
+
+func (merged *MergedTree) removeByPointer(path []string) error {
+	if len(path) == 0 {
+		merged.tree = nil
+		merged.sources = make(map[string]string)
+		return nil
+	}
+	var err error
+	merged.tree, err = modifyByPointer(merged.tree, path, removeChild)
+	if err != nil {
+		return err
+	}
+	sourcePath := merged.getSourcePath(path)
+	if sourcePath == pointerToJsonPath(path) {
+		merged.removeSources(sourcePath)
+	}
+	return nil
+}
+
+// Returns json path of the leaf which contains the value. Path goes through
+// objects only. Arrays are leaves.
+func (merged *MergedTree) getSourcePath(path []string) string {
+	res := "$"
+	node := merged.tree
+	for _, key := range path {
+		nodeMap, ok := node.(map[string]interface{})
+		if !ok {
+			return res
+		}
+		res += "." + key
+		node = nodeMap[key]
+	}
+	return res
+}
+
+func (merged *MergedTree) addSources(path string, value any, name string) {
+	valueMap, ok := value.(map[string]interface{})
+	if !ok || len(valueMap) == 0 {
+		merged.sources[path] = name
+		return
+	}
+	for key, val := range valueMap {
+		merged.addSources(path+"."+key, val, name)
+	}
+}
+
+func (merged *MergedTree) removeSources(path string) {
+	for key := range merged.sources {
+		if key == path || strings.HasPrefix(key, path+".") || strings.HasPrefix(key, path+"[") {
+			delete(merged.sources, key)
+		}
+	}
+}
+
+// Parse RFC 6901 json pointer.
+func parseJsonPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.New("json pointer '" + pointer + "' should start with '/'")
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func pointerToJsonPath(path []string) string {
+	return "$." + strings.Join(path, ".")
+}
+
+func getByJsonPath(tree any, path string) (any, bool) {
+	if path == "$" {
+		return tree, true
+	}
+	return getTreeValue(tree, path, "$")
+}
+
+func getByPointer(tree any, path []string) (any, error) {
+	node := tree
+	for _, key := range path {
+		switch val := node.(type) {
+		case map[string]interface{}:
+			child, ok := val[key]
+			if !ok {
+				return nil, errors.New("key '" + key + "' not found")
+			}
+			node = child
+		case []interface{}:
+			indx, err := parseArrayIndex(key, len(val)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = val[indx]
+		default:
+			return nil, errors.New("key '" + key + "' not found")
+		}
+	}
+	return node, nil
+}
+
+// Modify parent of the last path element. Returns new node.
+func modifyByPointer(node any, path []string, modify func(parent any, key string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return modify(node, path[0])
+	}
+	switch val := node.(type) {
+	case map[string]interface{}:
+		child, ok := val[path[0]]
+		if !ok {
+			return nil, errors.New("key '" + path[0] + "' not found")
+		}
+		newChild, err := modifyByPointer(child, path[1:], modify)
+		if err != nil {
+			return nil, err
+		}
+		val[path[0]] = newChild
+		return val, nil
+	case []interface{}:
+		indx, err := parseArrayIndex(path[0], len(val)-1)
+		if err != nil {
+			return nil, err
+		}
+		newChild, err := modifyByPointer(val[indx], path[1:], modify)
+		if err != nil {
+			return nil, err
+		}
+		val[indx] = newChild
+		return val, nil
+	}
+	return nil, errors.New("key '" + path[0] + "' not found")
+}
+
+func setChild(parent any, key string, value any, replace bool) (any, error) {
+	switch val := parent.(type) {
+	case map[string]interface{}:
+		val[key] = value
+		return val, nil
+	case []interface{}:
+		if key == "-" && !replace {
+			return append(val, value), nil
+		}
+		maxIndx := len(val)
+		if replace {
+			maxIndx--
+		}
+		indx, err := parseArrayIndex(key, maxIndx)
+		if err != nil {
+			return nil, err
+		}
+		if replace {
+			val[indx] = value
+			return val, nil
+		}
+		val = append(val, nil)
+		copy(val[indx+1:], val[indx:])
+		val[indx] = value
+		return val, nil
+	}
+	return nil, errors.New("value can't be set to key '" + key + "'")
+}
+
+func removeChild(parent any, key string) (any, error) {
+	switch val := parent.(type) {
+	case map[string]interface{}:
+		_, ok := val[key]
+		if !ok {
+			return nil, errors.New("key '" + key + "' not found")
+		}
+		delete(val, key)
+		return val, nil
+	case []interface{}:
+		indx, err := parseArrayIndex(key, len(val)-1)
+		if err != nil {
+			return nil, err
+		}
+		return append(val[:indx], val[indx+1:]...), nil
+	}
+	return nil, errors.New("key '" + key + "' not found")
+}
+
+func parseArrayIndex(key string, maxIndx int) (int, error) {
+	indx, err := strconv.Atoi(key)
+	if err != nil || indx < 0 || indx > maxIndx || (len(key) > 1 && key[0] == '0') {
+		return 0, errors.New("incorrect array index '" + key + "'")
+	}
+	return indx, nil
+}
+
+func deepCopyTree(tree any) any {
+	switch val := tree.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			res[key] = deepCopyTree(item)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, item := range val {
+			res[i] = deepCopyTree(item)
+		}
+		return res
+	}
+	return tree
+}
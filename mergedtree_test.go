@@ -0,0 +1,137 @@
+package dynamictags
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	MERGE_BASE_DOC   = `{"server": {"host": "localhost", "port": 8080, "tags": ["a", "b"]}, "debug": true, "log": {"level": "info"}}`
+	MERGE_REGION_DOC = `{"server": {"host": "eu.example.com"}, "debug": null}`
+	MERGE_HOST_DOC   = `{"server": {"port": 9090}, "log": "stdout"}`
+	MERGE_PATCH_DOC  = `[
+		{"op": "test", "path": "/server/port", "value": 9090},
+		{"op": "add", "path": "/server/tags/-", "value": "c"},
+		{"op": "replace", "path": "/server/host", "value": "host1.eu.example.com"},
+		{"op": "copy", "from": "/server/port", "path": "/admin_port"},
+		{"op": "move", "from": "/log", "path": "/output"},
+		{"op": "remove", "path": "/server/tags/0"}
+	]`
+)
+
+type MergedTreeTestStruct struct {
+	Host  string   `json:"server.host"`
+	Port  int      `json:"server.port"`
+	Tags  []string `json:"server.tags"`
+	Debug bool     `json:"debug" default:"false"`
+}
+
+func parseMergeDoc(t *testing.T, content string) any {
+	var doc any
+	err := json.Unmarshal([]byte(content), &doc)
+	assert.Nil(t, err)
+	return doc
+}
+
+func TestMergePatch(t *testing.T) {
+	// Case 1. RFC 7396 examples
+	target := parseMergeDoc(t, `{"a": "b", "c": {"d": "e", "f": "g"}}`)
+	res := MergePatch(target, parseMergeDoc(t, `{"a": "z", "c": {"f": null}}`))
+	assert.Equal(t, parseMergeDoc(t, `{"a": "z", "c": {"d": "e"}}`), res)
+	// Target is not modified
+	assert.Equal(t, parseMergeDoc(t, `{"a": "b", "c": {"d": "e", "f": "g"}}`), target)
+	// Case 2. Arrays are replaced
+	res = MergePatch(parseMergeDoc(t, `{"a": [1, 2]}`), parseMergeDoc(t, `{"a": [3]}`))
+	assert.Equal(t, parseMergeDoc(t, `{"a": [3]}`), res)
+	// Case 3. Not object patch replaces target
+	assert.Equal(t, "value", MergePatch(parseMergeDoc(t, `{"a": 1}`), "value"))
+	// Case 4. Nulls are removed from new objects
+	res = MergePatch(parseMergeDoc(t, `{"a": "b"}`), parseMergeDoc(t, `{"a": {"bb": {"ccc": null}}}`))
+	assert.Equal(t, parseMergeDoc(t, `{"a": {"bb": {}}}`), res)
+}
+
+func TestMergedTreeSources(t *testing.T) {
+	merged := NewMergedTree()
+	merged.Merge("base.json", parseMergeDoc(t, MERGE_BASE_DOC))
+	merged.Merge("region.json", parseMergeDoc(t, MERGE_REGION_DOC))
+	merged.Merge("host.json", parseMergeDoc(t, MERGE_HOST_DOC))
+	assert.Equal(t, parseMergeDoc(t, `{"server": {"host": "eu.example.com", "port": 9090, "tags": ["a", "b"]}, "log": "stdout"}`), merged.GetTree())
+	// Case 1. Leaf from every document
+	source, ok := merged.GetSource("$.server.host")
+	assert.True(t, ok)
+	assert.Equal(t, "region.json", source)
+	source, ok = merged.GetSource("$.server.port")
+	assert.True(t, ok)
+	assert.Equal(t, "host.json", source)
+	source, ok = merged.GetSource("$.log")
+	assert.True(t, ok)
+	assert.Equal(t, "host.json", source)
+	// Case 2. Array element has source of array
+	source, ok = merged.GetSource("$.server.tags[1]")
+	assert.True(t, ok)
+	assert.Equal(t, "base.json", source)
+	// Case 3. Removed and replaced values have no source
+	_, ok = merged.GetSource("$.debug")
+	assert.False(t, ok)
+	_, ok = merged.GetSource("$.log.level")
+	assert.False(t, ok)
+	// Case 4. Object is not a leaf
+	_, ok = merged.GetSource("$.server")
+	assert.False(t, ok)
+	// Case 5. Merged tree can be processed
+	processor, err := NewJsonProcessor(merged.GetTree(), "$")
+	assert.Nil(t, err)
+	data := MergedTreeTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, MergedTreeTestStruct{Host: "eu.example.com", Port: 9090, Tags: []string{"a", "b"}}, data)
+}
+
+func TestMergedTreeApplyPatch(t *testing.T) {
+	merged := NewMergedTree()
+	merged.Merge("base.json", parseMergeDoc(t, MERGE_BASE_DOC))
+	merged.Merge("host.json", parseMergeDoc(t, MERGE_HOST_DOC))
+	// Case 1. Correct patch
+	err := merged.ApplyPatch("patch.json", parseMergeDoc(t, MERGE_PATCH_DOC))
+	assert.Nil(t, err)
+	assert.Equal(t, parseMergeDoc(t, `{"server": {"host": "host1.eu.example.com", "port": 9090, "tags": ["b", "c"]}, "debug": true, "output": "stdout", "admin_port": 9090}`), merged.GetTree())
+	source, _ := merged.GetSource("$.server.host")
+	assert.Equal(t, "patch.json", source)
+	source, _ = merged.GetSource("$.server.port")
+	assert.Equal(t, "host.json", source)
+	source, _ = merged.GetSource("$.server.tags[0]")
+	assert.Equal(t, "patch.json", source)
+	source, _ = merged.GetSource("$.output")
+	assert.Equal(t, "patch.json", source)
+	source, _ = merged.GetSource("$.debug")
+	assert.Equal(t, "base.json", source)
+	_, ok := merged.GetSource("$.log")
+	assert.False(t, ok)
+	// Case 2. Failed patch doesn't change tree
+	tree := deepCopyTree(merged.GetTree())
+	err = merged.ApplyPatch("bad.json", parseMergeDoc(t, `[
+		{"op": "replace", "path": "/debug", "value": false},
+		{"op": "test", "path": "/server/port", "value": 1}
+	]`))
+	assert.NotNil(t, err)
+	assert.Equal(t, tree, merged.GetTree())
+	source, _ = merged.GetSource("$.debug")
+	assert.Equal(t, "base.json", source)
+	// Case 3. Incorrect operations
+	err = merged.ApplyPatch("bad.json", parseMergeDoc(t, `[{"op": "remove", "path": "/absent"}]`))
+	assert.NotNil(t, err)
+	err = merged.ApplyPatch("bad.json", parseMergeDoc(t, `[{"op": "replace", "path": "/server/tags/5", "value": 1}]`))
+	assert.NotNil(t, err)
+	err = merged.ApplyPatch("bad.json", parseMergeDoc(t, `[{"op": "unknown", "path": "/debug"}]`))
+	assert.NotNil(t, err)
+	err = merged.ApplyPatch("bad.json", parseMergeDoc(t, `[{"op": "move", "from": "/server", "path": "/server/inner"}]`))
+	assert.NotNil(t, err)
+	err = merged.ApplyPatch("bad.json", parseMergeDoc(t, `{"op": "remove", "path": "/debug"}`))
+	assert.NotNil(t, err)
+	// Case 4. Escaped pointer
+	err = merged.ApplyPatch("escape.json", parseMergeDoc(t, `[{"op": "add", "path": "/a~1b", "value": "c"}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, "c", merged.GetTree().(map[string]interface{})["a/b"])
+}
@@ -0,0 +1,83 @@
+package dynamictags
+
+import "os"
+
+const (
+	PROFILE_KEY  = "APP_PROFILE"
+	PROFILES_KEY = "profiles"
+)
+
+// Returns selected profile name. Profile is get from the dictionary
+// 'APP_PROFILE' key. If the key is not defined 'APP_PROFILE' environment
+// variable is used.
+// Parameters:
+//   - dictionary dictionary. Can be nil
+//
+// Returns:
+//   - profile name or empty string if profile is not selected
+func ResolveProfile(dictionary map[string]string) string {
+	return resolveProfile(dictionary, os.LookupEnv)
+}
+
+func resolveProfile(dictionary map[string]string, lookup EnvLookupFunc) string {
+	profile, ok := dictionary[PROFILE_KEY]
+	if ok {
+		return profile
+	}
+	profile, _ = lookup(PROFILE_KEY)
+	return profile
+}
+
+// Apply profile overlay to configuration tree. Profile specific values
+// are defined in the 'profiles.<profile>' section of the root object and
+// deep merged on top of the tree (see DeepMerge). If overlay is applied
+// 'profiles' section is removed from the result. For example for 'prod'
+// profile:
+//
+//	{
+//	  "database": {"host": "localhost", "port": 5432},
+//	  "profiles": {
+//	    "prod": {"database": {"host": "db.prod"}}
+//	  }
+//	}
+//
+// result is '{"database": {"host": "db.prod", "port": 5432}}'. Tree
+// processors apply overlay of the processing profile automatically (see
+// DynamicTagProcessor.SetProfile). Source tree is not modified.
+// Parameters:
+//   - tree configuration tree
+//   - profile profile name
+//
+// Returns:
+//   - configuration tree for the profile. The same tree if profile is not
+//     selected or tree has no overlay for the profile
+func ApplyProfile(tree any, profile string) any {
+	res, _ := applyProfile(tree, profile)
+	return res
+}
+
+// Returns tree with profile overlay and true if overlay is found.
+func applyProfile(tree any, profile string) (any, bool) {
+	if profile == "" {
+		return tree, false
+	}
+	treeMap, ok := tree.(map[string]interface{})
+	if !ok {
+		return tree, false
+	}
+	profilesMap, ok := treeMap[PROFILES_KEY].(map[string]interface{})
+	if !ok {
+		return tree, false
+	}
+	overlay, ok := profilesMap[profile]
+	if !ok {
+		return tree, false
+	}
+	res := make(map[string]interface{}, len(treeMap))
+	for key, val := range treeMap {
+		if key != PROFILES_KEY {
+			res[key] = val
+		}
+	}
+	return DeepMerge(res, overlay), true
+}
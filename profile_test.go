@@ -0,0 +1,173 @@
+package dynamictags
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	PROFILE_TEST_YAML = `
+database:
+  host: localhost
+  port: 5432
+profiles:
+  prod:
+    database:
+      host: db.prod
+  staging:
+    database:
+      port: 6543
+`
+)
+
+type ProfileTestStruct struct {
+	Host    string `yaml:"host" default:"localhost"`
+	Port    int    `yaml:"port" default:"5432"`
+	Timeout int    `default:"5" default.prod:"30"`
+	Debug   bool   `env:"PROFILE_TEST_DEBUG" env.prod:"PROFILE_TEST_PROD_DEBUG" default:"true" default.prod:"false"`
+}
+
+func TestApplyProfile(t *testing.T) {
+	tree, err := ReadYamlDocument([]byte(PROFILE_TEST_YAML), 0)
+	assert.NoError(t, err)
+	// Case 1. Profile overlay is applied
+	res := ApplyProfile(tree, "prod")
+	assert.Equal(t, map[string]interface{}{
+		"database": map[string]interface{}{"host": "db.prod", "port": 5432},
+	}, res)
+	// Case 2. Profile is not selected. Tree is not changed
+	assert.Equal(t, tree, ApplyProfile(tree, ""))
+	// Case 3. Unknown profile
+	assert.Equal(t, tree, ApplyProfile(tree, "dev"))
+	// Case 4. Source tree is not modified
+	_, ok := tree.(map[string]interface{})[PROFILES_KEY]
+	assert.True(t, ok)
+	// Case 5. Tree without profiles
+	assert.Equal(t, "value", ApplyProfile("value", "prod"))
+	// Case 6. 'profiles' is not an overlay section
+	list := map[string]interface{}{PROFILES_KEY: []interface{}{"default", "admin"}}
+	assert.Equal(t, list, ApplyProfile(list, "prod"))
+}
+
+func TestResolveProfile(t *testing.T) {
+	t.Setenv(PROFILE_KEY, "staging")
+	// Case 1. Profile from environment variable
+	assert.Equal(t, "staging", ResolveProfile(nil))
+	// Case 2. Dictionary has priority
+	assert.Equal(t, "prod", ResolveProfile(map[string]string{PROFILE_KEY: "prod"}))
+}
+
+func TestProcessorProfile(t *testing.T) {
+	t.Setenv(PROFILE_KEY, "")
+	t.Setenv("PROFILE_TEST_PROD_DEBUG", "true")
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewEnvTagConverter())
+	processor.AddTagConverter(NewDefaultTagConverter())
+	// Case 1. Profile is not selected
+	data := ProfileTestStruct{}
+	err := processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ProfileTestStruct{Host: "localhost", Port: 5432, Timeout: 5, Debug: true}, data)
+	// Case 2. Profile from dictionary activates profile tags
+	processor.SetDictionaryValue(PROFILE_KEY, "prod")
+	assert.Equal(t, "prod", processor.GetProfile())
+	data = ProfileTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ProfileTestStruct{Host: "localhost", Port: 5432, Timeout: 30, Debug: true}, data)
+	// Case 3. Explicit profile has priority
+	processor.SetProfile("staging")
+	data = ProfileTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ProfileTestStruct{Host: "localhost", Port: 5432, Timeout: 5, Debug: true}, data)
+}
+
+func TestLoadFSProfile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config.yaml": &fstest.MapFile{Data: []byte(PROFILE_TEST_YAML)},
+	}
+	t.Setenv(PROFILE_KEY, "staging")
+	processor, err := LoadFSWithRoot(fsys, "config.yaml", "$.database")
+	assert.NoError(t, err)
+	data := ProfileTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", data.Host)
+	assert.Equal(t, 6543, data.Port)
+	assert.Equal(t, 5, data.Timeout)
+}
+
+type ProfileListTestStruct struct {
+	Profiles []string `json:"profiles"`
+}
+
+func TestLoadFSProfilesField(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config.json": &fstest.MapFile{Data: []byte(`{"profiles": ["default", "admin"]}`)},
+	}
+	t.Setenv(PROFILE_KEY, "")
+	processor, err := LoadFS(fsys, "config.json")
+	assert.NoError(t, err)
+	// Case 1. Profile is not selected
+	data := ProfileListTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "admin"}, data.Profiles)
+	// Case 2. Profile is selected but 'profiles' is not an overlay section
+	processor.SetProfile("prod")
+	data = ProfileListTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "admin"}, data.Profiles)
+}
+
+func TestProcessorProfileOverlay(t *testing.T) {
+	t.Setenv(PROFILE_KEY, "")
+	tree, err := ReadYamlDocument([]byte(PROFILE_TEST_YAML), 0)
+	assert.NoError(t, err)
+	processor, err := NewYamlProcessor([]byte(PROFILE_TEST_YAML), "$.database")
+	assert.NoError(t, err)
+	// Case 1. Profile is not selected
+	data := ProfileTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", data.Host)
+	assert.Equal(t, 5432, data.Port)
+	// Case 2. Profile from dictionary selects overlay
+	processor.SetDictionaryValue(PROFILE_KEY, "staging")
+	data = ProfileTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", data.Host)
+	assert.Equal(t, 6543, data.Port)
+	// Case 3. Explicit profile has priority
+	processor.SetProfile("prod")
+	data = ProfileTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "db.prod", data.Host)
+	assert.Equal(t, 5432, data.Port)
+	// Case 4. Json and configuration processors
+	type JsonProfileStruct struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+	jsonProcessor, err := NewJsonProcessor(tree, "$.database")
+	assert.NoError(t, err)
+	jsonProcessor.SetProfile("prod")
+	jsonData := JsonProfileStruct{}
+	err = jsonProcessor.Process(&jsonData, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, JsonProfileStruct{Host: "db.prod", Port: 5432}, jsonData)
+	configProcessor, err := NewConfigurationProcessor(tree, "$.database")
+	assert.NoError(t, err)
+	configProcessor.SetDictionaryValue(PROFILE_KEY, "staging")
+	jsonData = JsonProfileStruct{}
+	err = configProcessor.Process(&jsonData, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, JsonProfileStruct{Host: "localhost", Port: 6543}, jsonData)
+}
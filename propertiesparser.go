@@ -0,0 +1,152 @@
+package dynamictags
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Parse java properties content.
+// Supported syntax:
+//   - 'key=value', 'key:value' and 'key value' pairs
+//   - comments. Lines started with '#' or '!'
+//   - line continuation. If line ends with odd number of '\' the next line
+//     (without leading whitespaces) is appended to the value
+//   - escapes '\t', '\n', '\r', '\f', '\uXXXX'. Other escaped characters
+//     are used as is (for example '\=' or '\ ' in keys)
+//
+// Parameters:
+//   - content properties content
+//
+// Returns:
+//   - key value map
+//   - error in case of syntax error (*SyntaxError)
+func ReadProperties(content []byte) (map[string]string, error) {
+	res := make(map[string]string)
+	text := string(content)
+	offset := 0
+	for offset < len(text) {
+		lineStart := offset
+		line, next := readPropertiesLine(text, offset)
+		offset = next
+		line = strings.TrimLeft(line, " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		keyEnd := findPropertiesKeyEnd(line)
+		value := strings.TrimLeft(line[keyEnd:], " \t\f")
+		if value != "" && (value[0] == '=' || value[0] == ':') {
+			value = strings.TrimLeft(value[1:], " \t\f")
+		}
+		key, err := unescapeProperties(line[:keyEnd])
+		if err != nil {
+			return nil, newSyntaxError(text, lineStart, err.Error())
+		}
+		res[key], err = unescapeProperties(value)
+		if err != nil {
+			return nil, newSyntaxError(text, lineStart, err.Error())
+		}
+	}
+	return res, nil
+}
+
+// Read logical line. Continuation lines are joined without leading whitespaces.
+func readPropertiesLine(text string, offset int) (string, int) {
+	var builder strings.Builder
+	isContinuation := false
+	for offset < len(text) {
+		end := strings.IndexByte(text[offset:], '\n')
+		next := len(text)
+		if end >= 0 {
+			next = offset + end + 1
+			end = offset + end
+		} else {
+			end = len(text)
+		}
+		line := strings.TrimRight(text[offset:end], "\r")
+		offset = next
+		if isContinuation {
+			line = strings.TrimLeft(line, " \t\f")
+		}
+		trimmed := strings.TrimRight(line, "\\")
+		if (len(line)-len(trimmed))%2 == 0 {
+			builder.WriteString(line)
+			break
+		}
+		builder.WriteString(line[:len(line)-1])
+		isContinuation = true
+	}
+	return builder.String(), offset
+}
+
+// Returns index of the first unescaped key separator.
+func findPropertiesKeyEnd(line string) int {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':', ' ', '\t', '\f':
+			return i
+		}
+	}
+	return len(line)
+}
+
+func unescapeProperties(str string) (string, error) {
+	if !strings.Contains(str, "\\") {
+		return str, nil
+	}
+	var builder strings.Builder
+	for i := 0; i < len(str); i++ {
+		if str[i] != '\\' || i+1 >= len(str) {
+			builder.WriteByte(str[i])
+			continue
+		}
+		i++
+		switch str[i] {
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			code, err := parseUnicodeEscape(str, i)
+			if err != nil {
+				return "", err
+			}
+			i += 4
+			// Characters outside of BMP are written as surrogate pair
+			if utf16.IsSurrogate(code) && strings.HasPrefix(str[i+1:], "\\u") {
+				low, err := parseUnicodeEscape(str, i+2)
+				if err != nil {
+					return "", err
+				}
+				code = utf16.DecodeRune(code, low)
+				i += 6
+			}
+			builder.WriteRune(code)
+		default:
+			builder.WriteByte(str[i])
+		}
+	}
+	return builder.String(), nil
+}
+
+// Parse 'XXXX' part of '\uXXXX' escape.
+// Parameters:
+//   - str string
+//   - indx index of 'u' character
+func parseUnicodeEscape(str string, indx int) (rune, error) {
+	if indx+5 > len(str) {
+		return 0, errors.New("incorrect unicode escape in '" + str + "'")
+	}
+	code, err := strconv.ParseUint(str[indx+1:indx+5], 16, 16)
+	if err != nil {
+		return 0, errors.New("incorrect unicode escape in '" + str + "'")
+	}
+	return rune(code), nil
+}
@@ -0,0 +1,45 @@
+package dynamictags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const PROPERTIES_PARSER_DATA = `
+# comment
+! comment
+a.b.c = value
+colon:value
+space value
+  indented = yes
+multi = first, \
+        second
+escaped\ key\=1 = tab\tvalue
+unicode = \u00e9t\u00e9 \uD83D\uDE00
+backslash = C:\\path\\
+empty
+`
+
+func TestReadProperties(t *testing.T) {
+	res, err := ReadProperties([]byte(PROPERTIES_PARSER_DATA))
+	assert.NoError(t, err)
+	assert.Equal(t, "value", res["a.b.c"])
+	assert.Equal(t, "value", res["colon"])
+	assert.Equal(t, "value", res["space"])
+	assert.Equal(t, "yes", res["indented"])
+	assert.Equal(t, "first, second", res["multi"])
+	assert.Equal(t, "tab\tvalue", res["escaped key=1"])
+	assert.Equal(t, "été 😀", res["unicode"])
+	assert.Equal(t, `C:\path\`, res["backslash"])
+	assert.Equal(t, "", res["empty"])
+	assert.Equal(t, 9, len(res))
+}
+
+func TestReadPropertiesErrors(t *testing.T) {
+	_, err := ReadProperties([]byte("a = 1\nb = \\u00z1"))
+	assert.Error(t, err)
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, syntaxErr.Line)
+}
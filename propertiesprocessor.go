@@ -0,0 +1,29 @@
+package dynamictags
+
+// Create processor to process 'properties' tag.
+// This processor replace structure field with 'properties' tag
+// by value get from properties file. Example usage:
+//
+//	content, err := os.ReadFile("server.properties")
+//	if err != nil {
+//	  return err
+//	}
+//	processor, err := NewPropertiesProcessor(content)
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&serverConfiguration, nil)
+//
+// Returns:
+//   - Properties tag processor if success.
+//   - error if content has syntax error
+func NewPropertiesProcessor(content []byte) (*DynamicTagProcessor, error) {
+	values, err := ReadProperties(content)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewPropertiesTagConverter(values))
+	return &processor, nil
+}
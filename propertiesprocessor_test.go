@@ -0,0 +1,42 @@
+package dynamictags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_PROPERTIES_TAG = "properties"
+	PROPERTIES_PROC_DATA    = `
+app.name = test
+app.datasource.url = jdbc:postgresql://localhost/db
+app.datasource.pool.size = 10
+`
+)
+
+type PropertiesPoolTestStruct struct {
+	Size int `properties:"size"`
+}
+
+type PropertiesDataSourceTestStruct struct {
+	Url  string                   `properties:"url"`
+	Pool PropertiesPoolTestStruct `properties:"pool"`
+}
+
+type PropertiesTestStruct struct {
+	Name       string                         `properties:"$.app.name"`
+	DataSource PropertiesDataSourceTestStruct `properties:"app.datasource"`
+}
+
+func TestPropertiesConversion(t *testing.T) {
+	processor, err := NewPropertiesProcessor([]byte(PROPERTIES_PROC_DATA))
+	assert.NoError(t, err)
+	assert.Equal(t, EXPECTED_PROPERTIES_TAG, processor.converters[0].GetTag())
+	testStruct := PropertiesTestStruct{}
+	err = processor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", testStruct.Name)
+	assert.Equal(t, "jdbc:postgresql://localhost/db", testStruct.DataSource.Url)
+	assert.Equal(t, 10, testStruct.DataSource.Pool.Size)
+}
@@ -0,0 +1,56 @@
+package dynamictags
+
+import "reflect"
+
+const (
+	PROPERTIES_TAG = "properties"
+)
+
+type PropertiesTagConverter struct {
+	values map[string]string
+}
+
+// Set structure field with 'properties' tag to value from properties file.
+// Tag value is dotted key (like 'properties:"a.b.c"'). Keys of nested
+// structures are composed in the same way as json paths. I.e. if structure
+// field has tag 'properties:"database"' tag 'properties:"port"' of its field means
+// key 'database.port'. Tag started with '$.' is absolute key.
+// Parameters:
+//   - content parsed properties content (see ReadProperties)
+//
+// Returns:
+//   - Properties tag converter.
+func NewPropertiesTagConverter(content map[string]string) TagConverterer {
+	return &PropertiesTagConverter{values: content}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *PropertiesTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	val, ok := conv.values[composeFlatKey(tag, path)]
+	return val, ok, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv PropertiesTagConverter) GetTag() string {
+	return PROPERTIES_TAG
+}
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv PropertiesTagConverter) HasEncryptedValues() bool {
+	return true
+}
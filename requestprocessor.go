@@ -0,0 +1,52 @@
+package dynamictags
+
+import (
+	"net/http"
+)
+
+// Create processor to bind http request to structure. Processor process
+// 'path', 'query', 'header', 'cookie', 'form' and 'default' tags (in this
+// order of priority). Values are converted in the same way as values of
+// other sources. Request values are never decrypted (values like
+// 'ENC[...]' are bound as is), so client can't use server key to decrypt
+// values. Validation of bound values is out of scope of the processor,
+// validate the structure after binding (for example with a validation
+// library). Example usage:
+//
+//	type GetUserRequest struct {
+//	  Id      int64    `path:"id"`
+//	  Fields  []string `query:"fields"`
+//	  TraceId string   `header:"X-Trace-Id"`
+//	  Session string   `cookie:"session"`
+//	  Limit   int      `query:"limit" default:"20"`
+//	}
+//	request := GetUserRequest{}
+//	err := NewRequestProcessor(r).Process(&request, nil)
+//
+// Parameters:
+//   - req http request
+//
+// Returns:
+//   - Request tag processor.
+func NewRequestProcessor(req *http.Request) *DynamicTagProcessor {
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewPathTagConverter(req))
+	processor.AddTagConverter(NewQueryTagConverter(req))
+	processor.AddTagConverter(NewHeaderTagConverter(req))
+	processor.AddTagConverter(NewCookieTagConverter(req))
+	processor.AddTagConverter(NewFormTagConverter(req))
+	processor.AddTagConverter(NewDefaultTagConverter())
+	return &processor
+}
+
+// Bind http request to structure (see NewRequestProcessor).
+// Parameters:
+//   - req http request
+//   - data pointer to structure
+//
+// Returns:
+//   - error if value can't be converted
+func BindRequest(req *http.Request, data any) error {
+	return NewRequestProcessor(req).Process(data, nil)
+}
@@ -0,0 +1,160 @@
+package dynamictags
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+const (
+	QUERY_TAG  = "query"
+	HEADER_TAG = "header"
+	PATH_TAG   = "path"
+	FORM_TAG   = "form"
+	COOKIE_TAG = "cookie"
+	// Maximum memory used to parse multipart form. Rest of the form is
+	// stored in temporary files
+	FORM_MAX_MEMORY = 32 << 20
+)
+
+// Returns all values of the request parameter.
+type requestLookupFunc func(req *http.Request, name string) ([]string, error)
+
+type RequestTagConverter struct {
+	tag     string
+	request *http.Request
+	lookup  requestLookupFunc
+}
+
+// Set structure field with 'query' tag to value of url query parameter.
+// If field is a slice all values of the parameter are set. Example usage:
+//
+//	type ListRequest struct {
+//	  Limit int      `query:"limit" default:"20"`
+//	  Tags  []string `query:"tag"`
+//	}
+//
+// Parameters:
+//   - req http request
+//
+// Returns:
+//   - Query tag converter.
+func NewQueryTagConverter(req *http.Request) TagConverterer {
+	return &RequestTagConverter{tag: QUERY_TAG, request: req, lookup: lookupQuery}
+}
+
+// Set structure field with 'header' tag to value of request header. Header
+// name is case insensitive.
+// Parameters:
+//   - req http request
+//
+// Returns:
+//   - Header tag converter.
+func NewHeaderTagConverter(req *http.Request) TagConverterer {
+	return &RequestTagConverter{tag: HEADER_TAG, request: req, lookup: lookupHeader}
+}
+
+// Set structure field with 'path' tag to value of path wildcard (see
+// http.Request.PathValue). For example for pattern '/users/{id}' tag
+// 'path:"id"' is used. Empty value is not set.
+// Parameters:
+//   - req http request
+//
+// Returns:
+//   - Path tag converter.
+func NewPathTagConverter(req *http.Request) TagConverterer {
+	return &RequestTagConverter{tag: PATH_TAG, request: req, lookup: lookupPath}
+}
+
+// Set structure field with 'form' tag to value of form field. Url encoded
+// and multipart forms are supported. Query parameters are not used (see
+// NewQueryTagConverter).
+// Parameters:
+//   - req http request
+//
+// Returns:
+//   - Form tag converter.
+func NewFormTagConverter(req *http.Request) TagConverterer {
+	return &RequestTagConverter{tag: FORM_TAG, request: req, lookup: lookupForm}
+}
+
+// Set structure field with 'cookie' tag to value of request cookie.
+// Parameters:
+//   - req http request
+//
+// Returns:
+//   - Cookie tag converter.
+func NewCookieTagConverter(req *http.Request) TagConverterer {
+	return &RequestTagConverter{tag: COOKIE_TAG, request: req, lookup: lookupCookie}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *RequestTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	values, err := conv.lookup(conv.request, tag)
+	if err != nil || len(values) == 0 {
+		return nil, false, err
+	}
+	if v.Kind() != reflect.Slice || v.Type() == rawMessageType {
+		return values[0], true, nil
+	}
+	res := make([]interface{}, len(values))
+	for i, val := range values {
+		res[i] = val
+	}
+	return res, true, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv RequestTagConverter) GetTag() string {
+	return conv.tag
+}
+
+func lookupQuery(req *http.Request, name string) ([]string, error) {
+	return req.URL.Query()[name], nil
+}
+
+func lookupHeader(req *http.Request, name string) ([]string, error) {
+	return req.Header.Values(name), nil
+}
+
+func lookupPath(req *http.Request, name string) ([]string, error) {
+	val := req.PathValue(name)
+	if val == "" {
+		return nil, nil
+	}
+	return []string{val}, nil
+}
+
+func lookupForm(req *http.Request, name string) ([]string, error) {
+	if req.PostForm == nil {
+		err := req.ParseMultipartForm(FORM_MAX_MEMORY)
+		if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return nil, err
+		}
+	}
+	return req.PostForm[name], nil
+}
+
+func lookupCookie(req *http.Request, name string) ([]string, error) {
+	cookie, err := req.Cookie(name)
+	if errors.Is(err, http.ErrNoCookie) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []string{cookie.Value}, nil
+}
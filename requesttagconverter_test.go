@@ -0,0 +1,157 @@
+package dynamictags
+
+import (
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	REQUEST_TEST_URL = "/users/42?limit=5&tag=a&tag=b&ids=1&ids=2"
+)
+
+type RequestTestStruct struct {
+	Limit int      `query:"limit"`
+	Tags  []string `query:"tag"`
+	Ids   []int    `query:"ids"`
+	Name  string   `form:"name"`
+	Trace string   `header:"x-trace-id"`
+	Token string   `cookie:"token"`
+	Id    int64    `path:"id"`
+}
+
+func TestRequestTagConverter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, REQUEST_TEST_URL, nil)
+	req.Header.Add("X-Trace-Id", "trace1")
+	req.AddCookie(&http.Cookie{Name: "token", Value: "secret"})
+	req.SetPathValue("id", "42")
+	data := RequestTestStruct{}
+	// Case 1. Single value
+	conv := NewQueryTagConverter(req)
+	assert.Equal(t, QUERY_TAG, conv.GetTag())
+	field, _ := reflect.TypeOf(data).FieldByName("Limit")
+	val, ok, err := conv.GetSimpleValue("limit", field, reflect.ValueOf(&data).Elem().FieldByName("Limit"), "$")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "5", val)
+	// Case 2. Slice gets all values
+	field, _ = reflect.TypeOf(data).FieldByName("Tags")
+	val, ok, err = conv.GetSimpleValue("tag", field, reflect.ValueOf(&data).Elem().FieldByName("Tags"), "$")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"a", "b"}, val)
+	// Case 3. Absent value is not set
+	_, ok, err = conv.GetSimpleValue("absent", field, reflect.ValueOf(&data).Elem().FieldByName("Tags"), "$")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	_, ok, err = NewCookieTagConverter(req).GetSimpleValue("absent", field, reflect.ValueOf(&data).Elem().FieldByName("Token"), "$")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	_, ok, err = NewPathTagConverter(req).GetSimpleValue("absent", field, reflect.ValueOf(&data).Elem().FieldByName("Id"), "$")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	// Case 4. Tags
+	assert.Equal(t, HEADER_TAG, NewHeaderTagConverter(req).GetTag())
+	assert.Equal(t, PATH_TAG, NewPathTagConverter(req).GetTag())
+	assert.Equal(t, FORM_TAG, NewFormTagConverter(req).GetTag())
+	assert.Equal(t, COOKIE_TAG, NewCookieTagConverter(req).GetTag())
+}
+
+func TestRequestProcessor(t *testing.T) {
+	// Case 1. Url encoded form
+	form := url.Values{}
+	form.Set("name", "John")
+	req := httptest.NewRequest(http.MethodPost, REQUEST_TEST_URL, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("X-Trace-Id", "trace1")
+	req.AddCookie(&http.Cookie{Name: "token", Value: "secret"})
+	req.SetPathValue("id", "42")
+	data := RequestTestStruct{}
+	err := BindRequest(req, &data)
+	assert.Nil(t, err)
+	assert.Equal(t, RequestTestStruct{
+		Limit: 5,
+		Tags:  []string{"a", "b"},
+		Ids:   []int{1, 2},
+		Name:  "John",
+		Trace: "trace1",
+		Token: "secret",
+		Id:    42,
+	}, data)
+	// Case 2. Multipart form
+	body := &strings.Builder{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("name", "Jane")
+	writer.Close()
+	req = httptest.NewRequest(http.MethodPost, "/?name=Query", strings.NewReader(body.String()))
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	data = RequestTestStruct{}
+	err = BindRequest(req, &data)
+	assert.Nil(t, err)
+	assert.Equal(t, "Jane", data.Name)
+	// Case 3. Query parameters are not form values
+	req = httptest.NewRequest(http.MethodGet, "/?name=Query", nil)
+	data = RequestTestStruct{}
+	err = BindRequest(req, &data)
+	assert.Nil(t, err)
+	assert.Equal(t, "", data.Name)
+	// Case 4. Incorrect value
+	req = httptest.NewRequest(http.MethodGet, "/?limit=five", nil)
+	err = BindRequest(req, &data)
+	assert.NotNil(t, err)
+	// Case 5. Encrypted values are not decrypted
+	t.Setenv(ENCRYPTION_KEY_ENV, TEST_ENCRYPTION_KEY)
+	key, err := ParseEncryptionKey(TEST_ENCRYPTION_KEY)
+	assert.Nil(t, err)
+	encrypted, err := EncryptValue("Jane", key)
+	assert.Nil(t, err)
+	req = httptest.NewRequest(http.MethodGet, "/?x="+url.QueryEscape(encrypted), nil)
+	req.Header.Set("X-Trace-Id", "ENC[AES256_GCM,data:AAAA,iv:AAAA]")
+	var encData struct {
+		X     string `query:"x"`
+		Trace string `header:"x-trace-id"`
+	}
+	err = BindRequest(req, &encData)
+	assert.Nil(t, err)
+	assert.Equal(t, encrypted, encData.X)
+	assert.Equal(t, "ENC[AES256_GCM,data:AAAA,iv:AAAA]", encData.Trace)
+	// Case 6. Typed slice default is used if parameter is absent
+	var sliceData struct {
+		Ids   []int           `query:"ids" default:"1"`
+		Waits []time.Duration `query:"wait" default:"1s,2s"`
+	}
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	err = BindRequest(req, &sliceData)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1}, sliceData.Ids)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, sliceData.Waits)
+	req = httptest.NewRequest(http.MethodGet, "/?ids=3&ids=4&wait=5ms", nil)
+	err = BindRequest(req, &sliceData)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{3, 4}, sliceData.Ids)
+	assert.Equal(t, []time.Duration{5 * time.Millisecond}, sliceData.Waits)
+}
+
+func TestRequestProcessorPattern(t *testing.T) {
+	type Request struct {
+		Id    int    `path:"id"`
+		Limit int    `query:"limit" default:"20"`
+		Trace string `header:"X-Trace-Id" default:"none"`
+	}
+	var data Request
+	var bindErr error
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		bindErr = BindRequest(r, &data)
+	})
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/7", nil))
+	assert.Nil(t, bindErr)
+	assert.Equal(t, Request{Id: 7, Limit: 20, Trace: "none"}, data)
+}
@@ -0,0 +1,134 @@
+package dynamictags
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SECRET_TAG = "secret"
+)
+
+type SecretTagConverter struct {
+	client *VaultClient
+	mutex  *sync.Mutex
+	// Secrets cache. Is not nil in converter of Process call only
+	cache           map[string]*VaultSecret
+	leaseExpiration time.Time
+}
+
+// Set structure field with 'secret' tag to value of Vault secret field.
+// Tag value is secret path and field name separated by '#' (like
+// 'secret:"kv/data/${ENV}/db#password"'). Every secret is read only once
+// during one Process call. If secret or field doesn't exist the field
+// is skipped. Secrets with lease (like dynamic database credentials) should
+// be reloaded before lease expiration (see LeaseExpiration). Example usage:
+//
+//	converter := NewSecretTagConverter(NewVaultClient(""))
+//	processor.AddTagConverter(converter)
+//	err := processor.Process(&configuration, nil)
+//	...
+//	expiration, ok := converter.(*SecretTagConverter).LeaseExpiration()
+//
+// Parameters:
+//   - client Vault client
+//
+// Returns:
+//   - Secret tag converter.
+func NewSecretTagConverter(client *VaultClient) TagConverterer {
+	return &SecretTagConverter{client: client, mutex: &sync.Mutex{}}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *SecretTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	secretPath, field, ok := strings.Cut(tag, "#")
+	if !ok || secretPath == "" || field == "" {
+		return nil, false, errors.New("secret tag '" + tag + "' should have format 'path#field'")
+	}
+	secret, err := conv.getSecret(secretPath)
+	if err != nil || secret == nil {
+		return nil, false, err
+	}
+	val, ok := secret.Data[field]
+	if !ok || val == nil {
+		return nil, false, nil
+	}
+	return val, true, nil
+}
+
+func (conv *SecretTagConverter) getSecret(secretPath string) (*VaultSecret, error) {
+	conv.mutex.Lock()
+	defer conv.mutex.Unlock()
+	secret, ok := conv.cache[secretPath]
+	if ok {
+		return secret, nil
+	}
+	secret, err := conv.client.Read(secretPath)
+	if err != nil {
+		return nil, err
+	}
+	if conv.cache != nil {
+		conv.cache[secretPath] = secret
+	}
+	if secret != nil && secret.LeaseDuration > 0 {
+		expiration := time.Now().Add(secret.LeaseDuration)
+		if conv.leaseExpiration.IsZero() || expiration.Before(conv.leaseExpiration) {
+			conv.leaseExpiration = expiration
+		}
+	}
+	return secret, nil
+}
+
+// Returns the earliest lease expiration time of secrets read during the
+// last finished Process call. Configuration should be reloaded before this time.
+// Returns:
+//   - lease expiration time
+//   - false if read secrets have no lease
+func (conv *SecretTagConverter) LeaseExpiration() (time.Time, bool) {
+	conv.mutex.Lock()
+	defer conv.mutex.Unlock()
+	return conv.leaseExpiration, !conv.leaseExpiration.IsZero()
+}
+
+// Returns converter which caches secrets during one Process call.
+// Returns:
+//   - converter of Process call
+func (conv *SecretTagConverter) BeginProcess() TagConverterer {
+	return &SecretTagConverter{client: conv.client, mutex: &sync.Mutex{}, cache: make(map[string]*VaultSecret)}
+}
+
+// Store lease expiration of secrets read during Process call. If Process
+// calls are concurrent lease expiration of the last finished call is stored.
+// Parameters:
+//   - converter converter of Process call
+func (conv *SecretTagConverter) EndProcess(converter TagConverterer) {
+	processConv, ok := converter.(*SecretTagConverter)
+	if !ok {
+		return
+	}
+	expiration, _ := processConv.LeaseExpiration()
+	conv.mutex.Lock()
+	defer conv.mutex.Unlock()
+	conv.leaseExpiration = expiration
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv SecretTagConverter) GetTag() string {
+	return SECRET_TAG
+}
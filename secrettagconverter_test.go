@@ -0,0 +1,70 @@
+package dynamictags
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_SECRET_TAG = "secret"
+)
+
+func TestSecretConverter(t *testing.T) {
+	fake := newFakeVault()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := NewVaultClient(server.URL)
+	client.SetToken(VAULT_TEST_TOKEN)
+	conv := NewSecretTagConverter(client)
+	assert.Equal(t, EXPECTED_SECRET_TAG, conv.GetTag())
+	// Case 1 secret field
+	val, isSet, err := conv.GetSimpleValue("kv/data/prod/db#password", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, "secret", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 secret is not cached outside of Process call
+	_, _, err = conv.GetSimpleValue("kv/data/prod/db#user", reflect.StructField{}, reflect.Value{}, "$")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fake.getRequests("kv/data/prod/db"))
+	// Case 3 unknown field and secret
+	_, isSet, err = conv.GetSimpleValue("kv/data/prod/db#unknown", reflect.StructField{}, reflect.Value{}, "$")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+	_, isSet, err = conv.GetSimpleValue("kv/data/prod/unknown#password", reflect.StructField{}, reflect.Value{}, "$")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+	// Case 4 incorrect tag
+	_, _, err = conv.GetSimpleValue("kv/data/prod/db", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Error(t, err)
+	// Case 5 lease expiration
+	_, ok := conv.(*SecretTagConverter).LeaseExpiration()
+	assert.False(t, ok)
+	_, _, err = conv.GetSimpleValue("database/creds/app#username", reflect.StructField{}, reflect.Value{}, "$")
+	assert.NoError(t, err)
+	expiration, ok := conv.(*SecretTagConverter).LeaseExpiration()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiration, time.Minute)
+	// Case 6 overlapping Process calls have own caches and lease expirations
+	listener := conv.(ProcessListener)
+	firstConv := listener.BeginProcess()
+	_, _, err = firstConv.GetSimpleValue("database/creds/app#username", reflect.StructField{}, reflect.Value{}, "$")
+	assert.NoError(t, err)
+	_, _, err = firstConv.GetSimpleValue("kv/data/prod/db#password", reflect.StructField{}, reflect.Value{}, "$")
+	assert.NoError(t, err)
+	secondConv := listener.BeginProcess()
+	_, _, err = secondConv.GetSimpleValue("kv/data/prod/db#password", reflect.StructField{}, reflect.Value{}, "$")
+	assert.NoError(t, err)
+	listener.EndProcess(secondConv)
+	_, ok = conv.(*SecretTagConverter).LeaseExpiration()
+	assert.False(t, ok)
+	_, _, err = firstConv.GetSimpleValue("kv/data/prod/db#user", reflect.StructField{}, reflect.Value{}, "$")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, fake.getRequests("kv/data/prod/db"))
+	listener.EndProcess(firstConv)
+	_, ok = conv.(*SecretTagConverter).LeaseExpiration()
+	assert.True(t, ok)
+}
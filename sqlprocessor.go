@@ -0,0 +1,37 @@
+package dynamictags
+
+import "database/sql"
+
+// Create processor to process 'db' tag.
+// This processor replace structure field with 'db' tag
+// by value from database table with key and value columns. Example usage:
+//
+//	type Limits struct {
+//	  Requests int `db:"tenant.${TENANT}.limit"`
+//	}
+//	processor, err := NewSqlProcessor(db, "settings", "key", "value")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.SetDictionaryValue("TENANT", "acme")
+//	processor.Process(&limits, nil)
+//
+// Parameters:
+//   - db database
+//   - table table name
+//   - keyColumn name of key column
+//   - valueColumn name of value column
+//
+// Returns:
+//   - Database tag processor if success.
+//   - error if table or column name is not a valid identifier
+func NewSqlProcessor(db *sql.DB, table string, keyColumn string, valueColumn string) (*DynamicTagProcessor, error) {
+	converter, err := NewSqlTagConverter(db, table, keyColumn, valueColumn)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(converter)
+	return &processor, nil
+}
@@ -0,0 +1,47 @@
+package dynamictags
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SqlTestStruct struct {
+	Tenant struct {
+		Limit int    `db:"limit" default:"10"`
+		Name  string `db:"name" default:"unknown"`
+	} `db:"tenant.${TENANT}"`
+	Global int `db:"tenant.globex.limit"`
+}
+
+func TestSqlProcessor(t *testing.T) {
+	database := fakeSqlDatabases[FAKE_SQL_TENANTS]
+	database.reset()
+	db, err := sql.Open(FAKE_SQL_DRIVER, FAKE_SQL_TENANTS)
+	assert.NoError(t, err)
+	defer db.Close()
+	processor, err := NewSqlProcessor(db, "settings", "key", "value")
+	assert.NoError(t, err)
+	processor.AddTagConverter(NewDefaultTagConverter())
+	// Case 1 table is read once per Process call
+	processor.SetDictionaryValue("TENANT", "acme")
+	data := SqlTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, data.Tenant.Limit)
+	assert.Equal(t, "Acme", data.Tenant.Name)
+	assert.Equal(t, 200, data.Global)
+	assert.Len(t, database.getQueries(), 1)
+	// Case 2 unknown tenant
+	processor.SetDictionaryValue("TENANT", "initech")
+	data = SqlTestStruct{}
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, data.Tenant.Limit)
+	assert.Equal(t, "unknown", data.Tenant.Name)
+	assert.Len(t, database.getQueries(), 2)
+	// Case 3 incorrect table name
+	_, err = NewSqlProcessor(db, "settings; DROP TABLE settings", "key", "value")
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package dynamictags
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+const (
+	DB_TAG = "db"
+)
+
+// Part of sql identifier: bare name, double quoted or back quoted name.
+var sqlIdentifierPart = `([A-Za-z_][A-Za-z0-9_$]*|"[^"]+"|` + "`[^`]+`)"
+var sqlColumnRegexp = regexp.MustCompile(`^` + sqlIdentifierPart + `$`)
+var sqlTableRegexp = regexp.MustCompile(`^` + sqlIdentifierPart + `(\.` + sqlIdentifierPart + `)?$`)
+
+type SqlTagConverter struct {
+	db    *sql.DB
+	query string
+	args  []any
+	mutex *sync.Mutex
+	// Table rows cache. Is used in converter of Process call only
+	cache     map[string]sql.NullString
+	isProcess bool
+}
+
+// Set structure field with 'db' tag to value from database table with
+// key and value columns (like 'settings(key, value)'). Tag value is key.
+// Keys of nested structures are composed in the same way as json paths.
+// I.e. if structure field has tag 'db:"tenant.${TENANT}"' tag 'db:"limit"'
+// of its field means key 'tenant.<tenant>.limit'. Tag started with '$.'
+// is absolute key. Table is read once during one Process call. Rows with
+// NULL value are skipped. Example usage:
+//
+//	db, err := sql.Open("postgres", dsn)
+//	...
+//	converter, err := NewSqlTagConverter(db, "settings", "key", "value")
+//
+// Table and column names are inserted into the query as is, so they are
+// validated. Names can be bare identifiers (like 'settings' or
+// 'config.settings') or quoted by the database rules (like '"key"' or
+// '`key`') if name is a reserved word.
+// Parameters:
+//   - db database
+//   - table table name
+//   - keyColumn name of key column
+//   - valueColumn name of value column
+//
+// Returns:
+//   - Database tag converter.
+//   - error if table or column name is not a valid identifier
+func NewSqlTagConverter(db *sql.DB, table string, keyColumn string, valueColumn string) (TagConverterer, error) {
+	if !sqlTableRegexp.MatchString(table) {
+		return nil, errors.New("incorrect table name '" + table + "'")
+	}
+	for _, column := range []string{keyColumn, valueColumn} {
+		if !sqlColumnRegexp.MatchString(column) {
+			return nil, errors.New("incorrect column name '" + column + "'")
+		}
+	}
+	return NewSqlQueryTagConverter(db, "SELECT "+keyColumn+", "+valueColumn+" FROM "+table), nil
+}
+
+// Create database tag converter with custom query. Query should return two
+// columns: key and value. For example
+// 'SELECT name, value FROM settings WHERE tenant = $1'.
+// Parameters:
+//   - db database
+//   - query query
+//   - args query arguments
+//
+// Returns:
+//   - Database tag converter.
+func NewSqlQueryTagConverter(db *sql.DB, query string, args ...any) TagConverterer {
+	return &SqlTagConverter{db: db, query: query, args: args, mutex: &sync.Mutex{}}
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *SqlTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	values, err := conv.getValues()
+	if err != nil {
+		return nil, false, err
+	}
+	val, ok := values[composeFlatKey(tag, path)]
+	if !ok || !val.Valid {
+		return nil, false, nil
+	}
+	return val.String, true, nil
+}
+
+func (conv *SqlTagConverter) getValues() (map[string]sql.NullString, error) {
+	conv.mutex.Lock()
+	defer conv.mutex.Unlock()
+	if conv.cache != nil {
+		return conv.cache, nil
+	}
+	values, err := conv.readValues()
+	if err != nil {
+		return nil, err
+	}
+	if conv.isProcess {
+		conv.cache = values
+	}
+	return values, nil
+}
+
+func (conv *SqlTagConverter) readValues() (map[string]sql.NullString, error) {
+	rows, err := conv.db.QueryContext(context.Background(), conv.query, conv.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := make(map[string]sql.NullString)
+	for rows.Next() {
+		var key string
+		var val sql.NullString
+		err = rows.Scan(&key, &val)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = val
+	}
+	return values, rows.Err()
+}
+
+// Returns converter which caches table rows during one Process call.
+// Returns:
+//   - converter of Process call
+func (conv *SqlTagConverter) BeginProcess() TagConverterer {
+	return &SqlTagConverter{db: conv.db, query: conv.query, args: conv.args, mutex: &sync.Mutex{}, isProcess: true}
+}
+
+// Finish Process call. Cache of the call is dropped with its converter.
+// Parameters:
+//   - converter converter of Process call
+func (conv *SqlTagConverter) EndProcess(converter TagConverterer) {
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv SqlTagConverter) GetTag() string {
+	return DB_TAG
+}
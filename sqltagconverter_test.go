@@ -0,0 +1,208 @@
+package dynamictags
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_DB_TAG  = "db"
+	FAKE_SQL_DRIVER  = "dynamictags_fake"
+	FAKE_SQL_TENANTS = "tenants"
+	FAKE_SQL_BROKEN  = "broken"
+)
+
+// Fake database. Every query returns all rows of the database.
+type fakeSqlDatabase struct {
+	mutex     sync.Mutex
+	rows      [][]driver.Value
+	queries   []string
+	lastArgs  []driver.Value
+	queryFail bool
+}
+
+var fakeSqlDatabases = map[string]*fakeSqlDatabase{
+	FAKE_SQL_TENANTS: {rows: [][]driver.Value{
+		{"tenant.acme.limit", "100"},
+		{"tenant.acme.name", "Acme"},
+		{"tenant.globex.limit", "200"},
+		{"tenant.acme.disabled", nil},
+	}},
+	FAKE_SQL_BROKEN: {queryFail: true},
+}
+
+type fakeSqlDriver struct{}
+
+type fakeSqlConn struct {
+	database *fakeSqlDatabase
+}
+
+type fakeSqlStmt struct {
+	conn  *fakeSqlConn
+	query string
+}
+
+type fakeSqlRows struct {
+	rows  [][]driver.Value
+	index int
+}
+
+func init() {
+	sql.Register(FAKE_SQL_DRIVER, fakeSqlDriver{})
+}
+
+func (drv fakeSqlDriver) Open(name string) (driver.Conn, error) {
+	database, ok := fakeSqlDatabases[name]
+	if !ok {
+		return nil, errors.New("unknown database")
+	}
+	return &fakeSqlConn{database: database}, nil
+}
+
+func (conn *fakeSqlConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSqlStmt{conn: conn, query: query}, nil
+}
+
+func (conn *fakeSqlConn) Close() error {
+	return nil
+}
+
+func (conn *fakeSqlConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (stmt *fakeSqlStmt) Close() error {
+	return nil
+}
+
+func (stmt *fakeSqlStmt) NumInput() int {
+	return -1
+}
+
+func (stmt *fakeSqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec is not supported")
+}
+
+func (stmt *fakeSqlStmt) Query(args []driver.Value) (driver.Rows, error) {
+	database := stmt.conn.database
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+	database.queries = append(database.queries, stmt.query)
+	database.lastArgs = args
+	if database.queryFail {
+		return nil, errors.New("query failed")
+	}
+	return &fakeSqlRows{rows: database.rows}, nil
+}
+
+func (rows *fakeSqlRows) Columns() []string {
+	return []string{"key", "value"}
+}
+
+func (rows *fakeSqlRows) Close() error {
+	return nil
+}
+
+func (rows *fakeSqlRows) Next(dest []driver.Value) error {
+	if rows.index >= len(rows.rows) {
+		return io.EOF
+	}
+	copy(dest, rows.rows[rows.index])
+	rows.index++
+	return nil
+}
+
+func (database *fakeSqlDatabase) reset() {
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+	database.queries = nil
+	database.lastArgs = nil
+}
+
+func (database *fakeSqlDatabase) getQueries() []string {
+	database.mutex.Lock()
+	defer database.mutex.Unlock()
+	return database.queries
+}
+
+func TestSqlConverter(t *testing.T) {
+	database := fakeSqlDatabases[FAKE_SQL_TENANTS]
+	database.reset()
+	db, err := sql.Open(FAKE_SQL_DRIVER, FAKE_SQL_TENANTS)
+	assert.NoError(t, err)
+	defer db.Close()
+	conv, err := NewSqlTagConverter(db, "settings", "name", "value")
+	assert.NoError(t, err)
+	assert.Equal(t, EXPECTED_DB_TAG, conv.GetTag())
+	// Case 1 nested key
+	val, isSet, err := conv.GetSimpleValue("limit", reflect.StructField{}, reflect.Value{}, "$.tenant.acme")
+	assert.Equal(t, "100", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SELECT name, value FROM settings"}, database.getQueries())
+	// Case 2 NULL value and unknown key
+	_, isSet, err = conv.GetSimpleValue("tenant.acme.disabled", reflect.StructField{}, reflect.Value{}, "$")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+	_, isSet, err = conv.GetSimpleValue("tenant.unknown.limit", reflect.StructField{}, reflect.Value{}, "$")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+	// Case 3 table is cached during process only
+	assert.Len(t, database.getQueries(), 3)
+	listener := conv.(ProcessListener)
+	processConv := listener.BeginProcess()
+	processConv.GetSimpleValue("tenant.acme.limit", reflect.StructField{}, reflect.Value{}, "$")
+	processConv.GetSimpleValue("tenant.acme.name", reflect.StructField{}, reflect.Value{}, "$")
+	// Overlapping Process call doesn't clear the cache
+	otherConv := listener.BeginProcess()
+	otherConv.GetSimpleValue("tenant.acme.limit", reflect.StructField{}, reflect.Value{}, "$")
+	listener.EndProcess(otherConv)
+	processConv.GetSimpleValue("tenant.acme.name", reflect.StructField{}, reflect.Value{}, "$")
+	listener.EndProcess(processConv)
+	assert.Len(t, database.getQueries(), 5)
+	// Case 4 custom query
+	conv = NewSqlQueryTagConverter(db, "SELECT name, value FROM settings WHERE tenant = ?", "acme")
+	_, _, err = conv.GetSimpleValue("tenant.acme.limit", reflect.StructField{}, reflect.Value{}, "$")
+	assert.NoError(t, err)
+	assert.Equal(t, []driver.Value{"acme"}, database.lastArgs)
+	// Case 5 query error
+	brokenDb, err := sql.Open(FAKE_SQL_DRIVER, FAKE_SQL_BROKEN)
+	assert.NoError(t, err)
+	defer brokenDb.Close()
+	conv, err = NewSqlTagConverter(brokenDb, "settings", "key", "value")
+	assert.NoError(t, err)
+	_, _, err = conv.GetSimpleValue("tenant.acme.limit", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Error(t, err)
+}
+
+func TestSqlConverterIdentifiers(t *testing.T) {
+	database := fakeSqlDatabases[FAKE_SQL_TENANTS]
+	database.reset()
+	db, err := sql.Open(FAKE_SQL_DRIVER, FAKE_SQL_TENANTS)
+	assert.NoError(t, err)
+	defer db.Close()
+	// Case 1 schema and quoted names
+	conv, err := NewSqlTagConverter(db, "config.settings", `"key"`, "`value`")
+	assert.NoError(t, err)
+	_, _, err = conv.GetSimpleValue("tenant.acme.limit", reflect.StructField{}, reflect.Value{}, "$")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SELECT \"key\", `value` FROM config.settings"}, database.getQueries())
+	// Case 2 incorrect names
+	_, err = NewSqlTagConverter(db, "settings WHERE 1=1", "key", "value")
+	assert.Error(t, err)
+	_, err = NewSqlTagConverter(db, "settings", "key, password", "value")
+	assert.Error(t, err)
+	_, err = NewSqlTagConverter(db, "settings", "key", `"value" FROM users --"`)
+	assert.Error(t, err)
+	_, err = NewSqlTagConverter(db, "", "key", "value")
+	assert.Error(t, err)
+	_, err = NewSqlTagConverter(db, "a.b.c", "key", "value")
+	assert.Error(t, err)
+}
@@ -12,7 +12,11 @@ const (
 	END_INCLUDE   = "}"
 )
 
-// Process string. Replace ${ENVIRONMENT_VARIABLE} by value from dictionary or by
+// Function which returns value of environment variable. Has the same
+// semantic as os.LookupEnv.
+type EnvLookupFunc func(key string) (string, bool)
+
+// Process string. Replace each ${ENVIRONMENT_VARIABLE} by value from dictionary or by
 // environment variable if no dictionary value found or replace by empty string
 // if no dictionary and no environment variable found
 // Parameters:
@@ -22,27 +26,64 @@ const (
 // Returns:
 //   - result string or error
 func ProcessString(str string, dictionary map[string]string) (string, error) {
+	return ProcessStringWithLookup(str, dictionary, os.LookupEnv)
+}
+
+// Process string. The same as ProcessString but environment variables
+// values are get by lookup function.
+// Parameters:
+//   - str source string
+//   - dictionary dictionary
+//   - lookup environment variables lookup function (for example os.LookupEnv)
+//
+// Returns:
+//   - result string or error
+func ProcessStringWithLookup(str string, dictionary map[string]string, lookup EnvLookupFunc) (string, error) {
 	stIndx := strings.Index(str, START_INCLUDE)
 	if stIndx < 0 {
 		return str, nil
 	}
-	endIndx := strings.LastIndex(str, END_INCLUDE)
-	if endIndx < 0 || endIndx < stIndx {
+	endIndx := findCloseBrace(str, stIndx+len(START_INCLUDE))
+	if endIndx < 0 {
 		msg := fmt.Sprintf("incorrect tag structure. String '%s'. No closed brace", str)
 		return "", errors.New(msg)
 	}
 	contentStr := str[stIndx+len(START_INCLUDE) : endIndx]
-	content, err := ProcessString(contentStr, dictionary)
+	content, err := ProcessStringWithLookup(contentStr, dictionary, lookup)
 	if err != nil {
 		return "", err
 	}
 	val, ok := dictionary[content]
 	if !ok {
-		val, ok = os.LookupEnv(content)
+		val, ok = lookup(content)
 		if !ok {
 			val = ""
 		}
 	}
-	res := str[:stIndx] + val + str[endIndx+1:]
-	return res, nil
+	tail, err := ProcessStringWithLookup(str[endIndx+len(END_INCLUDE):], dictionary, lookup)
+	if err != nil {
+		return "", err
+	}
+	return str[:stIndx] + val + tail, nil
+}
+
+// Returns index of close brace. Nested '${...}' are skipped.
+// Returns:
+//   - index of close brace or -1 if not found
+func findCloseBrace(str string, start int) int {
+	depth := 0
+	for i := start; i < len(str); i++ {
+		if strings.HasPrefix(str[i:], START_INCLUDE) {
+			depth++
+			i += len(START_INCLUDE) - 1
+			continue
+		}
+		if strings.HasPrefix(str[i:], END_INCLUDE) {
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
 }
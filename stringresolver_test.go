@@ -92,3 +92,44 @@ func TestErrors(t *testing.T) {
 	_, err = ProcessString(TWO_LEVEL_NO_CLOSE_BRACE, nil)
 	assert.Error(t, err)
 }
+
+func TestSeveralSubstitutes(t *testing.T) {
+	dict := map[string]string{"HOST": "localhost", "PORT": "8080", "NAME": "PORT", "SUFFIX": "ST", "PREFIX": "HO"}
+	// Case 1 several substitutes in one string
+	res, err := ProcessString("http://${HOST}:${PORT}/", dict)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/", res)
+	// Case 2 nested substitute followed by another substitute
+	res, err = ProcessString("${${NAME}}-${HOST}", dict)
+	assert.NoError(t, err)
+	assert.Equal(t, "8080-localhost", res)
+	// Case 3 several nested substitutes in one key
+	res, err = ProcessString("${${PREFIX}${SUFFIX}}:${${NAME}}", dict)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:8080", res)
+	// Case 4 close brace outside of substitute is kept
+	res, err = ProcessString("{${HOST}}", dict)
+	assert.NoError(t, err)
+	assert.Equal(t, "{localhost}", res)
+	// Case 5 second substitute has no close brace
+	_, err = ProcessString("${HOST}:${PORT", dict)
+	assert.Error(t, err)
+}
+
+func TestLookup(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		if key == TEST_SUBST {
+			return SIMPLE_SUBST_VALUE1, true
+		}
+		return "", false
+	}
+	// Case 1 value from lookup function
+	res, err := ProcessStringWithLookup(SIMPLE_STRING_SUBST, nil, lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, EXPECTED_SIMPLE_SUBST1, res)
+	// Case 2 dictionary value has priority
+	dict := map[string]string{TEST_SUBST: SIMPLE_SUBST_VALUE}
+	res, err = ProcessStringWithLookup(SIMPLE_STRING_SUBST, dict, lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, EXPECTED_SIMPLE_SUBST, res)
+}
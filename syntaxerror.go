@@ -0,0 +1,54 @@
+package dynamictags
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Syntax error in configuration content.
+type SyntaxError struct {
+	// File path. Empty if content is not read from file
+	File string
+	// Line number (starts from 1)
+	Line int
+	// Column number (starts from 1). 0 if column is unknown
+	Column int
+	// Error description
+	Msg string
+}
+
+// Returns error description with error position.
+func (err *SyntaxError) Error() string {
+	pos := fmt.Sprintf("line %d", err.Line)
+	if err.Column > 0 {
+		pos += fmt.Sprintf(", column %d", err.Column)
+	}
+	if err.File != "" {
+		pos = err.File + ": " + pos
+	}
+	return "syntax error at " + pos + ": " + err.Msg
+}
+
+// Create syntax error.
+// Parameters:
+//   - content parsed content
+//   - offset byte offset of the error in the content
+//   - msg error description
+//
+// Returns:
+//   - syntax error with calculated line and column
+func newSyntaxError(content string, offset int, msg string) *SyntaxError {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	line := 1
+	lineStart := 0
+	for i := 0; i < offset; i++ {
+		if content[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	column := utf8.RuneCountInString(content[lineStart:offset]) + 1
+	return &SyntaxError{Line: line, Column: column, Msg: msg}
+}
@@ -26,3 +26,93 @@ type TagConverterer interface {
 	// - tag
 	GetTag() string
 }
+
+// Optional interface for tag converter which should be notified about
+// processing start and end. For example converter can cache values during
+// one Process call. State of one Process call should be kept in the
+// converter returned by BeginProcess, so concurrent Process calls with the
+// same converter don't share it.
+type ProcessListener interface {
+	// This function is called before structure processing.
+	// Returns:
+	//   - converter used during the processing
+	BeginProcess() TagConverterer
+
+	// This function is called after structure processing (even in case of error).
+	// Parameters:
+	//   - converter converter returned by BeginProcess
+	EndProcess(converter TagConverterer)
+}
+
+// Optional interface for tag converter which paths have own syntax of
+// array element index. By default element path is the array path with
+// 0-based index (like '$.servers[0]').
+type ItemPathComposer interface {
+	// Returns path to the array element.
+	// Parameters:
+	//   - path path to the array
+	//   - index 0-based index of the element
+	//
+	// Returns:
+	//   - path to the element
+	ComposeItemPath(path string, index int) string
+}
+
+// Optional interface for tag converter which paths have own syntax of
+// nested structure path. By default path of nested structure is the parent
+// path and the structure tag separated by '.' (like '$.database').
+type TagPathComposer interface {
+	// Returns path to the nested structure.
+	// Parameters:
+	//   - path path to the parent structure
+	//   - tag processed tag of the nested structure
+	//
+	// Returns:
+	//   - path to the nested structure
+	ComposeTagPath(path string, tag string) string
+}
+
+// Optional interface for tag converter which values depend on profile (for
+// example configuration document with 'profiles' section, see ApplyProfile).
+// Processor calls WithProfile before processing and uses returned converter.
+type ProfileConverter interface {
+	// Returns converter for the profile.
+	// Parameters:
+	//   - profile profile name. Empty if profile is not selected
+	//
+	// Returns:
+	//   - converter for the profile. Can be the same converter
+	//   - error in case of error
+	WithProfile(profile string) (TagConverterer, error)
+}
+
+// Optional interface for tag converter which substitutes placeholders
+// itself. For example command line should be split to arguments before
+// substitution, otherwise value with spaces would be split to several
+// arguments. Processor calls GetRawValue instead of GetSimpleValue.
+type RawTagConverter interface {
+	// Returns conversion result.
+	// Parameters:
+	//   - tag raw tag value. Substrings like '${KEY}' are not replaced
+	//   - resolve function which replaces '${KEY}' substrings by dictionary
+	//     or environment variable value
+	//   - t reflect structure field
+	//   - v reflect structure value
+	//   - path json path to structure filed (like '$.InternalStructure.Data1')
+	//
+	// Returns:
+	//   - result value
+	//   - if 'false' result value will not be set
+	//   - error in case of error
+	GetRawValue(tag string, resolve func(str string) (string, error), t reflect.StructField, v reflect.Value, path string) (any, bool, error)
+}
+
+// Optional interface for tag converter which values can be encrypted (for
+// example values of configuration files stored in git). Values like
+// 'ENC[AES256_GCM,data:...,iv:...]' are decrypted only if they are got from
+// converter which implements this interface and returns true. Values of
+// other converters are used as is.
+type EncryptedValuesSource interface {
+	// Returns true if converter values can be encrypted.
+	HasEncryptedValues() bool
+}
@@ -0,0 +1,649 @@
+package dynamictags
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Parser of toml content. Result of parsing is the tree of map[string]interface{},
+// []interface{}, string, int64, float64, bool and time.Time values.
+type tomlParser struct {
+	content string
+	pos     int
+	root    map[string]interface{}
+	current map[string]interface{}
+	// Explicitly defined tables. Used to detect duplicated tables
+	tables map[string]bool
+}
+
+// Array of tables ('[[name]]') during parsing. Static arrays can't be
+// extended by '[[name]]' and arrays of tables can't be redefined by '[name]'.
+type tomlArrayTable []interface{}
+
+// Inline table during parsing. Inline tables can't be extended by tables
+// or dotted keys.
+type tomlInlineTable map[string]interface{}
+
+// Parse toml content.
+// Parameters:
+//   - content toml content
+//
+// Returns:
+//   - parsed tree
+//   - error in case of syntax error (*SyntaxError)
+func ReadToml(content []byte) (any, error) {
+	parser := tomlParser{
+		content: string(content),
+		root:    make(map[string]interface{}),
+		tables:  make(map[string]bool),
+	}
+	parser.current = parser.root
+	err := parser.parse()
+	if err != nil {
+		return nil, err
+	}
+	return normalizeTomlValue(parser.root), nil
+}
+
+// Convert parsing types to map[string]interface{} and []interface{}.
+func normalizeTomlValue(value any) any {
+	switch val := value.(type) {
+	case tomlInlineTable:
+		return normalizeTomlValue(map[string]interface{}(val))
+	case tomlArrayTable:
+		return normalizeTomlValue([]interface{}(val))
+	case map[string]interface{}:
+		for key, item := range val {
+			val[key] = normalizeTomlValue(item)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normalizeTomlValue(item)
+		}
+		return val
+	}
+	return value
+}
+
+func (parser *tomlParser) errorf(msg string) error {
+	return newSyntaxError(parser.content, parser.pos, msg)
+}
+
+func (parser *tomlParser) isEnd() bool {
+	return parser.pos >= len(parser.content)
+}
+
+func (parser *tomlParser) peek() byte {
+	if parser.isEnd() {
+		return 0
+	}
+	return parser.content[parser.pos]
+}
+
+func (parser *tomlParser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(parser.content[parser.pos:], prefix)
+}
+
+func (parser *tomlParser) skipSpaces() {
+	for !parser.isEnd() && (parser.peek() == ' ' || parser.peek() == '\t') {
+		parser.pos++
+	}
+}
+
+func (parser *tomlParser) skipComment() {
+	if parser.peek() != '#' {
+		return
+	}
+	for !parser.isEnd() && parser.peek() != '\n' {
+		parser.pos++
+	}
+}
+
+// Skip spaces, comments and new lines.
+func (parser *tomlParser) skipBlank() {
+	for !parser.isEnd() {
+		parser.skipSpaces()
+		parser.skipComment()
+		if parser.peek() == '\r' || parser.peek() == '\n' {
+			parser.pos++
+			continue
+		}
+		return
+	}
+}
+
+// Check that rest of line contains only spaces or comment.
+func (parser *tomlParser) expectLineEnd() error {
+	parser.skipSpaces()
+	parser.skipComment()
+	if parser.isEnd() {
+		return nil
+	}
+	if parser.hasPrefix("\r\n") {
+		parser.pos += 2
+		return nil
+	}
+	if parser.peek() == '\n' {
+		parser.pos++
+		return nil
+	}
+	return parser.errorf("unexpected character '" + string(parser.peek()) + "' at the end of line")
+}
+
+func (parser *tomlParser) parse() error {
+	for {
+		parser.skipBlank()
+		if parser.isEnd() {
+			return nil
+		}
+		var err error
+		if parser.hasPrefix("[[") {
+			err = parser.parseArrayTable()
+		} else if parser.peek() == '[' {
+			err = parser.parseTable()
+		} else {
+			err = parser.parseKeyValue(parser.current)
+		}
+		if err != nil {
+			return err
+		}
+		err = parser.expectLineEnd()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (parser *tomlParser) parseTable() error {
+	parser.pos++
+	keys, err := parser.parseKey()
+	if err != nil {
+		return err
+	}
+	if parser.peek() != ']' {
+		return parser.errorf("']' is expected")
+	}
+	parser.pos++
+	parent, err := parser.getTable(parser.root, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	switch parent[keys[len(keys)-1]].(type) {
+	case nil, map[string]interface{}:
+	default:
+		return parser.errorf("key '" + strings.Join(keys, ".") + "' is not a table")
+	}
+	table, err := parser.getTable(parent, keys[len(keys)-1:])
+	if err != nil {
+		return err
+	}
+	fullKey := strings.Join(keys, "\x00")
+	if parser.tables[fullKey] {
+		return parser.errorf("table '" + strings.Join(keys, ".") + "' already defined")
+	}
+	parser.tables[fullKey] = true
+	parser.current = table
+	return nil
+}
+
+func (parser *tomlParser) parseArrayTable() error {
+	parser.pos += 2
+	keys, err := parser.parseKey()
+	if err != nil {
+		return err
+	}
+	if !parser.hasPrefix("]]") {
+		return parser.errorf("']]' is expected")
+	}
+	parser.pos += 2
+	parent, err := parser.getTable(parser.root, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	lastKey := keys[len(keys)-1]
+	table := make(map[string]interface{})
+	switch existed := parent[lastKey].(type) {
+	case nil:
+		parent[lastKey] = tomlArrayTable{table}
+	case tomlArrayTable:
+		parent[lastKey] = append(existed, table)
+	default:
+		return parser.errorf("key '" + strings.Join(keys, ".") + "' is not an array of tables")
+	}
+	// Sub tables of the new array element can be defined again
+	prefix := strings.Join(keys, "\x00") + "\x00"
+	for key := range parser.tables {
+		if strings.HasPrefix(key, prefix) {
+			delete(parser.tables, key)
+		}
+	}
+	parser.current = table
+	return nil
+}
+
+// Returns table by keys. Creates missing tables. For arrays of tables
+// the last element is used. Static arrays and inline tables can't be
+// extended.
+func (parser *tomlParser) getTable(table map[string]interface{}, keys []string) (map[string]interface{}, error) {
+	for _, key := range keys {
+		switch existed := table[key].(type) {
+		case nil:
+			newTable := make(map[string]interface{})
+			table[key] = newTable
+			table = newTable
+		case map[string]interface{}:
+			table = existed
+		case tomlArrayTable:
+			if len(existed) == 0 {
+				return nil, parser.errorf("key '" + key + "' is not a table")
+			}
+			last, ok := existed[len(existed)-1].(map[string]interface{})
+			if !ok {
+				return nil, parser.errorf("key '" + key + "' is not a table")
+			}
+			table = last
+		default:
+			return nil, parser.errorf("key '" + key + "' is not a table")
+		}
+	}
+	return table, nil
+}
+
+func (parser *tomlParser) parseKeyValue(table map[string]interface{}) error {
+	keys, err := parser.parseKey()
+	if err != nil {
+		return err
+	}
+	if parser.peek() != '=' {
+		return parser.errorf("'=' is expected")
+	}
+	parser.pos++
+	parser.skipSpaces()
+	value, err := parser.parseValue()
+	if err != nil {
+		return err
+	}
+	parent, err := parser.getTable(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	lastKey := keys[len(keys)-1]
+	_, ok := parent[lastKey]
+	if ok {
+		return parser.errorf("duplicated key '" + strings.Join(keys, ".") + "'")
+	}
+	parent[lastKey] = value
+	return nil
+}
+
+// Parse dotted key. Spaces after the key are skipped.
+func (parser *tomlParser) parseKey() ([]string, error) {
+	keys := make([]string, 0, 1)
+	for {
+		parser.skipSpaces()
+		var key string
+		var err error
+		switch parser.peek() {
+		case '"':
+			key, err = parser.parseBasicString()
+		case '\'':
+			key, err = parser.parseLiteralString()
+		default:
+			start := parser.pos
+			for !parser.isEnd() && isTomlBareKeyChar(parser.peek()) {
+				parser.pos++
+			}
+			if start == parser.pos {
+				return nil, parser.errorf("key is expected")
+			}
+			key = parser.content[start:parser.pos]
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		parser.skipSpaces()
+		if parser.peek() != '.' {
+			return keys, nil
+		}
+		parser.pos++
+	}
+}
+
+func isTomlBareKeyChar(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '_' || ch == '-'
+}
+
+func (parser *tomlParser) parseValue() (any, error) {
+	switch {
+	case parser.hasPrefix(`"""`):
+		return parser.parseMultilineBasicString()
+	case parser.peek() == '"':
+		return parser.parseBasicString()
+	case parser.hasPrefix("'''"):
+		return parser.parseMultilineLiteralString()
+	case parser.peek() == '\'':
+		return parser.parseLiteralString()
+	case parser.peek() == '[':
+		return parser.parseArray()
+	case parser.peek() == '{':
+		return parser.parseInlineTable()
+	case parser.hasPrefix("true"):
+		parser.pos += len("true")
+		return true, nil
+	case parser.hasPrefix("false"):
+		parser.pos += len("false")
+		return false, nil
+	}
+	return parser.parseNumberOrDate()
+}
+
+func (parser *tomlParser) parseBasicString() (string, error) {
+	start := parser.pos
+	parser.pos++
+	var builder strings.Builder
+	for {
+		if parser.isEnd() || parser.peek() == '\n' {
+			parser.pos = start
+			return "", parser.errorf("unterminated string")
+		}
+		ch := parser.peek()
+		if ch == '"' {
+			parser.pos++
+			return builder.String(), nil
+		}
+		if ch == '\\' {
+			err := parser.parseEscape(&builder)
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+		builder.WriteByte(ch)
+		parser.pos++
+	}
+}
+
+func (parser *tomlParser) parseMultilineBasicString() (string, error) {
+	parser.pos += 3
+	parser.skipNewLine()
+	var builder strings.Builder
+	for {
+		if parser.isEnd() {
+			return "", parser.errorf("unterminated multiline string")
+		}
+		if parser.hasPrefix(`"""`) {
+			parser.pos += 3
+			// Up to two quotes are allowed right before the closing delimiter
+			for i := 0; i < 2 && parser.peek() == '"'; i++ {
+				builder.WriteByte('"')
+				parser.pos++
+			}
+			return builder.String(), nil
+		}
+		ch := parser.peek()
+		if ch == '\\' {
+			// Line ending backslash trims all whitespaces and new lines
+			rest := strings.TrimLeft(parser.content[parser.pos+1:], " \t")
+			if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+				parser.pos = len(parser.content) - len(rest)
+				for !parser.isEnd() && strings.ContainsRune(" \t\r\n", rune(parser.peek())) {
+					parser.pos++
+				}
+				continue
+			}
+			err := parser.parseEscape(&builder)
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+		builder.WriteByte(ch)
+		parser.pos++
+	}
+}
+
+func (parser *tomlParser) parseLiteralString() (string, error) {
+	quotePos := parser.pos
+	parser.pos++
+	start := parser.pos
+	for {
+		if parser.isEnd() || parser.peek() == '\n' {
+			parser.pos = quotePos
+			return "", parser.errorf("unterminated string")
+		}
+		if parser.peek() == '\'' {
+			res := parser.content[start:parser.pos]
+			parser.pos++
+			return res, nil
+		}
+		parser.pos++
+	}
+}
+
+func (parser *tomlParser) parseMultilineLiteralString() (string, error) {
+	parser.pos += 3
+	parser.skipNewLine()
+	end := strings.Index(parser.content[parser.pos:], "'''")
+	if end < 0 {
+		return "", parser.errorf("unterminated multiline string")
+	}
+	end += parser.pos
+	// Up to two quotes are allowed right before the closing delimiter
+	for i := 0; i < 2 && strings.HasPrefix(parser.content[end+1:], "'''"); i++ {
+		end++
+	}
+	res := parser.content[parser.pos:end]
+	parser.pos = end + 3
+	return res, nil
+}
+
+// Skip new line right after multiline string opening delimiter.
+func (parser *tomlParser) skipNewLine() {
+	if parser.hasPrefix("\r\n") {
+		parser.pos += 2
+	} else if parser.peek() == '\n' {
+		parser.pos++
+	}
+}
+
+func (parser *tomlParser) parseEscape(builder *strings.Builder) error {
+	parser.pos++
+	ch := parser.peek()
+	parser.pos++
+	switch ch {
+	case 'b':
+		builder.WriteByte('\b')
+	case 't':
+		builder.WriteByte('\t')
+	case 'n':
+		builder.WriteByte('\n')
+	case 'f':
+		builder.WriteByte('\f')
+	case 'r':
+		builder.WriteByte('\r')
+	case 'e':
+		builder.WriteByte(0x1b)
+	case '"':
+		builder.WriteByte('"')
+	case '\\':
+		builder.WriteByte('\\')
+	case 'u', 'U':
+		size := 4
+		if ch == 'U' {
+			size = 8
+		}
+		if parser.pos+size > len(parser.content) {
+			return parser.errorf("incorrect unicode escape")
+		}
+		code, err := strconv.ParseUint(parser.content[parser.pos:parser.pos+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return parser.errorf("incorrect unicode escape")
+		}
+		builder.WriteRune(rune(code))
+		parser.pos += size
+	default:
+		parser.pos--
+		return parser.errorf("incorrect escape sequence")
+	}
+	return nil
+}
+
+func (parser *tomlParser) parseArray() (any, error) {
+	parser.pos++
+	res := make([]interface{}, 0)
+	for {
+		parser.skipBlank()
+		if parser.peek() == ']' {
+			parser.pos++
+			return res, nil
+		}
+		value, err := parser.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, value)
+		parser.skipBlank()
+		switch parser.peek() {
+		case ',':
+			parser.pos++
+		case ']':
+			parser.pos++
+			return res, nil
+		default:
+			return nil, parser.errorf("',' or ']' is expected")
+		}
+	}
+}
+
+func (parser *tomlParser) parseInlineTable() (any, error) {
+	parser.pos++
+	res := make(tomlInlineTable)
+	parser.skipSpaces()
+	if parser.peek() == '}' {
+		parser.pos++
+		return res, nil
+	}
+	for {
+		parser.skipSpaces()
+		err := parser.parseKeyValue(map[string]interface{}(res))
+		if err != nil {
+			return nil, err
+		}
+		parser.skipSpaces()
+		switch parser.peek() {
+		case ',':
+			parser.pos++
+		case '}':
+			parser.pos++
+			return res, nil
+		default:
+			return nil, parser.errorf("',' or '}' is expected")
+		}
+	}
+}
+
+func (parser *tomlParser) parseNumberOrDate() (any, error) {
+	start := parser.pos
+	for !parser.isEnd() && isTomlValueChar(parser.peek()) {
+		parser.pos++
+	}
+	// Date and time can be separated by space
+	if parser.pos-start == 10 && parser.peek() == ' ' && parser.pos+3 < len(parser.content) &&
+		parser.content[parser.pos+3] == ':' {
+		parser.pos++
+		for !parser.isEnd() && isTomlValueChar(parser.peek()) {
+			parser.pos++
+		}
+	}
+	token := parser.content[start:parser.pos]
+	if token == "" {
+		return nil, parser.errorf("value is expected")
+	}
+	res, ok := parseTomlScalar(token)
+	if !ok {
+		parser.pos = start
+		return nil, parser.errorf("incorrect value '" + token + "'")
+	}
+	return res, nil
+}
+
+func isTomlValueChar(ch byte) bool {
+	return isTomlBareKeyChar(ch) || ch == '+' || ch == '.' || ch == ':'
+}
+
+func parseTomlScalar(token string) (any, bool) {
+	switch strings.TrimLeft(token, "+-") {
+	case "inf":
+		if strings.HasPrefix(token, "-") {
+			return math.Inf(-1), true
+		}
+		return math.Inf(1), true
+	case "nan":
+		return math.NaN(), true
+	}
+	if len(token) >= 5 && token[2] == ':' && isDigits(token[:2]) ||
+		len(token) >= 10 && token[4] == '-' && isDigits(token[:4]) {
+		return parseTomlDate(token)
+	}
+	if strings.HasPrefix(token, "0x") || strings.HasPrefix(token, "0o") || strings.HasPrefix(token, "0b") {
+		res, err := strconv.ParseInt(token, 0, 64)
+		return res, err == nil
+	}
+	digits := strings.TrimLeft(token, "+-")
+	if strings.Contains(token, "__") || strings.HasPrefix(digits, "_") || strings.HasSuffix(digits, "_") {
+		return nil, false
+	}
+	plain := strings.ReplaceAll(token, "_", "")
+	if strings.ContainsAny(plain, ".eE") {
+		res, err := strconv.ParseFloat(plain, 64)
+		return res, err == nil
+	}
+	if len(digits) > 1 && digits[0] == '0' {
+		// Leading zeros are not allowed
+		return nil, false
+	}
+	res, err := strconv.ParseInt(plain, 10, 64)
+	return res, err == nil
+}
+
+func isDigits(str string) bool {
+	for _, ch := range str {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+var tomlDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999",
+}
+
+func parseTomlDate(token string) (any, bool) {
+	token = strings.ToUpper(token)
+	if len(token) > 10 && token[10] == ' ' {
+		token = token[:10] + "T" + token[11:]
+	}
+	for i, layout := range tomlDateLayouts {
+		var res time.Time
+		var err error
+		if i == 0 {
+			res, err = time.Parse(layout, token)
+		} else {
+			// Local date and time
+			res, err = time.ParseInLocation(layout, token, time.Local)
+		}
+		if err == nil {
+			return res, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,285 @@
+package dynamictags
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const TOML_PARSER_DATA = `
+# Comment
+title = "TOML \"example\" \u00e9"
+literal = 'C:\Users\nodejs'
+multiline = """
+Roses are red
+Violets are \
+    blue"""
+multiliteral = '''
+raw\n text'''
+hex = 0xDEAD_beef
+oct = 0o755
+bin = 0b1101
+int = +1_000
+negative = -17
+float = 6.626e-34
+infinity = -inf
+nan = nan
+bool = false
+odt = 1979-05-27T07:32:00-08:00
+odt_space = 1979-05-27 07:32:00Z
+ldt = 1979-05-27T07:32:00.5
+ld = 1979-05-27
+lt = 07:32:00
+array = [ 1, 2, 3, ] # trailing comma
+nested = [ [ "a", 'b' ], [ 1.5 ] ]
+inline = { x = 1, y.z = "deep" }
+"quoted key" = 1
+dotted.key.value = true
+
+[server]
+host = "localhost"
+
+[server.tls]
+enabled = true
+
+[[products]]
+name = "Hammer"
+
+[[products]]
+name = "Nail"
+[products.size]
+length = 3
+`
+
+func TestReadToml(t *testing.T) {
+	res, err := ReadToml([]byte(TOML_PARSER_DATA))
+	assert.NoError(t, err)
+	tree := res.(map[string]interface{})
+	assert.Equal(t, "TOML \"example\" é", tree["title"])
+	assert.Equal(t, `C:\Users\nodejs`, tree["literal"])
+	assert.Equal(t, "Roses are red\nViolets are blue", tree["multiline"])
+	assert.Equal(t, `raw\n text`, tree["multiliteral"])
+	assert.Equal(t, int64(0xDEADBEEF), tree["hex"])
+	assert.Equal(t, int64(0755), tree["oct"])
+	assert.Equal(t, int64(13), tree["bin"])
+	assert.Equal(t, int64(1000), tree["int"])
+	assert.Equal(t, int64(-17), tree["negative"])
+	assert.Equal(t, 6.626e-34, tree["float"])
+	assert.Equal(t, math.Inf(-1), tree["infinity"])
+	assert.True(t, math.IsNaN(tree["nan"].(float64)))
+	assert.Equal(t, false, tree["bool"])
+	expectedTime := time.Date(1979, 5, 27, 15, 32, 0, 0, time.UTC)
+	assert.True(t, expectedTime.Equal(tree["odt"].(time.Time)))
+	assert.True(t, time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC).Equal(tree["odt_space"].(time.Time)))
+	assert.Equal(t, time.Date(1979, 5, 27, 7, 32, 0, 500000000, time.Local), tree["ldt"])
+	assert.Equal(t, time.Date(1979, 5, 27, 0, 0, 0, 0, time.Local), tree["ld"])
+	assert.Equal(t, 7, tree["lt"].(time.Time).Hour())
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, tree["array"])
+	assert.Equal(t, []interface{}{[]interface{}{"a", "b"}, []interface{}{1.5}}, tree["nested"])
+	assert.Equal(t, map[string]interface{}{"x": int64(1), "y": map[string]interface{}{"z": "deep"}}, tree["inline"])
+	assert.Equal(t, int64(1), tree["quoted key"])
+	assert.Equal(t, true, tree["dotted"].(map[string]interface{})["key"].(map[string]interface{})["value"])
+	server := tree["server"].(map[string]interface{})
+	assert.Equal(t, "localhost", server["host"])
+	assert.Equal(t, true, server["tls"].(map[string]interface{})["enabled"])
+	products := tree["products"].([]interface{})
+	assert.Equal(t, 2, len(products))
+	assert.Equal(t, "Hammer", products[0].(map[string]interface{})["name"])
+	assert.Equal(t, int64(3), products[1].(map[string]interface{})["size"].(map[string]interface{})["length"])
+}
+
+func TestReadTomlErrors(t *testing.T) {
+	// Case 1 duplicated key
+	_, err := ReadToml([]byte("a = 1\na = 2"))
+	assert.Error(t, err)
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, syntaxErr.Line)
+	// Case 2 duplicated table
+	_, err = ReadToml([]byte("[a]\nb = 1\n[a]\nc = 2"))
+	assert.Error(t, err)
+	// Case 3 unterminated string
+	_, err = ReadToml([]byte("a = \"value"))
+	assert.Error(t, err)
+	// Case 4 incorrect value
+	_, err = ReadToml([]byte("a = 0123"))
+	assert.Error(t, err)
+	// Case 5 garbage at the end of line
+	_, err = ReadToml([]byte("a = 1 b"))
+	assert.Error(t, err)
+	syntaxErr, ok = err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, syntaxErr.Line)
+	assert.Equal(t, 7, syntaxErr.Column)
+}
+
+func TestReadTomlArrayTables(t *testing.T) {
+	// Case 1 nested arrays of tables and subtables
+	res, err := ReadToml([]byte("[[fruit]]\nname = \"apple\"\n[fruit.physical]\ncolor = \"red\"\n[[fruit.variety]]\nname = \"red delicious\"\n[[fruit.variety]]\nname = \"granny smith\"\n[[fruit]]\nname = \"banana\"\n[[fruit.variety]]\nname = \"plantain\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"fruit": []interface{}{
+		map[string]interface{}{
+			"name":     "apple",
+			"physical": map[string]interface{}{"color": "red"},
+			"variety":  []interface{}{map[string]interface{}{"name": "red delicious"}, map[string]interface{}{"name": "granny smith"}},
+		},
+		map[string]interface{}{
+			"name":    "banana",
+			"variety": []interface{}{map[string]interface{}{"name": "plantain"}},
+		},
+	}}, res)
+	// Case 2 empty tables
+	res, err = ReadToml([]byte("[[a]]\n[[a]]\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{map[string]interface{}{}, map[string]interface{}{}}}, res)
+	// Case 3 dotted name
+	res, err = ReadToml([]byte("[[a.b]]\nc = 1\n[[a.b]]\nc = 2\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{
+		map[string]interface{}{"c": int64(1)},
+		map[string]interface{}{"c": int64(2)},
+	}}}, res)
+	// Case 4 array of inline tables
+	res, err = ReadToml([]byte("points = [ { x = 1, y = 2 },\n  { x = 7, y = 8 } ]\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"points": []interface{}{
+		map[string]interface{}{"x": int64(1), "y": int64(2)},
+		map[string]interface{}{"x": int64(7), "y": int64(8)},
+	}}, res)
+	// Case 5 table of the last array element
+	res, err = ReadToml([]byte("[[a]]\n[a.b]\nc = 1\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{
+		map[string]interface{}{"b": map[string]interface{}{"c": int64(1)}},
+	}}, res)
+	// Case 6 static array can't be extended
+	_, err = ReadToml([]byte("a = [1]\n[[a]]\n"))
+	assert.Error(t, err)
+	// Case 7 table and array of tables with the same name
+	_, err = ReadToml([]byte("[a]\n[[a]]\n"))
+	assert.Error(t, err)
+	_, err = ReadToml([]byte("[[a]]\n[a]\n"))
+	assert.Error(t, err)
+	// Case 8 unclosed header
+	_, err = ReadToml([]byte("[[a]\n"))
+	assert.Error(t, err)
+}
+
+func TestReadTomlInlineTables(t *testing.T) {
+	// Case 1 empty tables
+	res, err := ReadToml([]byte("a = {}\nb = {   }\nc = {x=1}\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{},
+		"b": map[string]interface{}{},
+		"c": map[string]interface{}{"x": int64(1)},
+	}, res)
+	// Case 2 nested tables
+	res, err = ReadToml([]byte("a = { b = { c = { d = 1 } }, e = [1, {f = 2}] }\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{
+		"b": map[string]interface{}{"c": map[string]interface{}{"d": int64(1)}},
+		"e": []interface{}{int64(1), map[string]interface{}{"f": int64(2)}},
+	}}, res)
+	// Case 3 quoted and dotted keys
+	res, err = ReadToml([]byte("name = { first = \"Tom\", \"last name\" = 'Preston', a.b.c = true }\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": map[string]interface{}{
+		"first":     "Tom",
+		"last name": "Preston",
+		"a":         map[string]interface{}{"b": map[string]interface{}{"c": true}},
+	}}, res)
+	// Case 4 inline table can't be extended
+	_, err = ReadToml([]byte("a = {b = 1}\n[a.c]\n"))
+	assert.Error(t, err)
+	_, err = ReadToml([]byte("a = {b = 1}\na.c = 2\n"))
+	assert.Error(t, err)
+	// Case 5 incorrect syntax
+	_, err = ReadToml([]byte("a = {b = 1,\nc = 2}\n"))
+	assert.Error(t, err)
+	_, err = ReadToml([]byte("a = {b = 1,}\n"))
+	assert.Error(t, err)
+	_, err = ReadToml([]byte("a = {b = 1, b = 2}\n"))
+	assert.Error(t, err)
+}
+
+func TestReadTomlMultilineStrings(t *testing.T) {
+	// Case 1 leading newline is trimmed
+	res, err := ReadToml([]byte("a = \"\"\"\nline\"\"\"\nb = '''\nline'''\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "line", "b": "line"}, res)
+	// Case 2 quotes inside string
+	res, err = ReadToml([]byte("a = \"\"\"Here are two quotation marks: \"\". Simple.\"\"\"\nb = \"\"\"\"This,\" she said.\"\"\"\"\nc = '''I [dw]on't need \\d{2} apples'''\nd = ''''That,' she said.''''\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"a": "Here are two quotation marks: \"\". Simple.",
+		"b": "\"This,\" she said.\"",
+		"c": "I [dw]on't need \\d{2} apples",
+		"d": "'That,' she said.'",
+	}, res)
+	// Case 3 line ending backslash
+	res, err = ReadToml([]byte("a = \"\"\"\\\n  The quick \\\n\n  brown fox.\\\n  \"\"\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "The quick brown fox."}, res)
+	// Case 4 crlf line endings
+	res, err = ReadToml([]byte("a = \"\"\"\r\nline1\r\nline2\"\"\"\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "line1\r\nline2"}, res)
+	// Case 5 escapes
+	res, err = ReadToml([]byte("a = \"\"\"tab\\there\\u00e9\\U0001F600\"\"\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "tab\thereé\U0001F600"}, res)
+	// Case 6 empty strings
+	res, err = ReadToml([]byte("a = \"\"\"\"\"\"\nb = ''''''\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "", "b": ""}, res)
+	// Case 7 unterminated strings
+	_, err = ReadToml([]byte("a = \"\"\"abc\n"))
+	assert.Error(t, err)
+	_, err = ReadToml([]byte("a = '''abc\n"))
+	assert.Error(t, err)
+	// Case 8 too many quotes
+	_, err = ReadToml([]byte("a = \"\"\"abc\"\"\"\"\"\"\n"))
+	assert.Error(t, err)
+	// Case 9 newline in basic string
+	_, err = ReadToml([]byte("a = \"abc\ndef\"\n"))
+	assert.Error(t, err)
+}
+
+func TestReadTomlDatetimes(t *testing.T) {
+	// Case 1 offset and fraction
+	res, err := ReadToml([]byte("a = 1979-05-27T00:32:00.999999-07:00\n"))
+	assert.NoError(t, err)
+	expected := time.Date(1979, 5, 27, 0, 32, 0, 999999000, time.FixedZone("", -7*3600))
+	assert.True(t, expected.Equal(res.(map[string]interface{})["a"].(time.Time)))
+	// Case 2 lowercase separators
+	res, err = ReadToml([]byte("a = 1987-07-05t17:45:00z\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": time.Date(1987, 7, 5, 17, 45, 0, 0, time.UTC)}, res)
+	// Case 3 local datetime with fraction
+	res, err = ReadToml([]byte("a = 1979-05-27T00:32:00.123\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": time.Date(1979, 5, 27, 0, 32, 0, 123000000, time.Local)}, res)
+	// Case 4 dates in array
+	res, err = ReadToml([]byte("a = [1979-05-27, 1979-05-28]\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{
+		time.Date(1979, 5, 27, 0, 0, 0, 0, time.Local),
+		time.Date(1979, 5, 28, 0, 0, 0, 0, time.Local),
+	}}, res)
+	// Case 5 comment after value
+	res, err = ReadToml([]byte("a = 1979-05-27T07:32:00Z # comment\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC)}, res)
+	// Case 6 incorrect values
+	_, err = ReadToml([]byte("a = 1979-13-27\n"))
+	assert.Error(t, err)
+	_, err = ReadToml([]byte("a = 1979-02-30T00:00:00Z\n"))
+	assert.Error(t, err)
+	_, err = ReadToml([]byte("a = 25:00:00\n"))
+	assert.Error(t, err)
+	_, err = ReadToml([]byte("a = 1979-05-27T07:32Z\n"))
+	assert.Error(t, err)
+}
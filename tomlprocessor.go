@@ -0,0 +1,33 @@
+package dynamictags
+
+// Create processor to process 'toml' tag.
+// This processor replace structure field with 'toml' tag
+// by value get from toml. Example usage:
+//
+//	content, err := os.ReadFile("serverconfiguration.toml")
+//	if err != nil {
+//	  return err
+//	}
+//	processor, err := NewTomlProcessor(content, "$.database")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&databaseConfiguration, nil)
+//
+// Returns:
+//   - Toml tag processor if success.
+//   - error if error occured during processor creation
+func NewTomlProcessor(content []byte, rootPath string) (*DynamicTagProcessor, error) {
+	doc, err := ReadToml(content)
+	if err != nil {
+		return nil, err
+	}
+	converter, err := NewTomlTagConverter(doc, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(converter)
+	return &processor, nil
+}
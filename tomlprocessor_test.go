@@ -0,0 +1,87 @@
+package dynamictags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TomlServerTestStruct struct {
+	Name  string   `toml:"name"`
+	Port  uint16   `toml:"port" default:"8080"`
+	Tags  []string `toml:"tags"`
+	Ratio float64  `toml:"ratio"`
+}
+
+type TomlDatabaseTestStruct struct {
+	Host    string    `toml:"host"`
+	Ports   []int     `toml:"ports"`
+	Updated time.Time `toml:"updated"`
+}
+
+type TomlTestStruct struct {
+	Title    string                 `toml:"title"`
+	Created  time.Time              `toml:"created"`
+	Default  time.Time              `default:"2024-01-02T03:04:05Z"`
+	Database TomlDatabaseTestStruct `toml:"database"`
+	Servers  []TomlServerTestStruct `toml:"servers"`
+}
+
+const TOML_PROC_DATA = `
+title = "test"
+created = 2024-05-27T07:32:00Z
+
+[database]
+host = "db.local"
+ports = [8000, 8001]
+updated = 2024-05-28T00:00:00+02:00
+
+[[servers]]
+name = "alpha"
+port = 10
+tags = ["a", "b"]
+ratio = 1
+
+[[servers]]
+name = "beta"
+`
+
+func TestCreateTomlProcessor(t *testing.T) {
+	// Case 1 correct create processor
+	proc, err := NewTomlProcessor([]byte(TOML_PROC_DATA), "$")
+	assert.NoError(t, err)
+	assert.NotNil(t, proc)
+
+	// Case 2 syntax error
+	proc, err = NewTomlProcessor([]byte("title = "), "$")
+	assert.Error(t, err)
+	assert.Nil(t, proc)
+
+	// Case 3 incorrect path
+	proc, err = NewTomlProcessor([]byte(TOML_PROC_DATA), INCORRECT_PATH)
+	assert.Error(t, err)
+	assert.Nil(t, proc)
+}
+
+func TestTomlConversion(t *testing.T) {
+	tomlProcessor, err := NewTomlProcessor([]byte(TOML_PROC_DATA), "$")
+	assert.NoError(t, err)
+	tomlProcessor.AddTagConverter(NewDefaultTagConverter())
+	testStruct := TomlTestStruct{}
+	err = tomlProcessor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", testStruct.Title)
+	assert.True(t, time.Date(2024, 5, 27, 7, 32, 0, 0, time.UTC).Equal(testStruct.Created))
+	assert.True(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Equal(testStruct.Default))
+	assert.Equal(t, "db.local", testStruct.Database.Host)
+	assert.Equal(t, []int{8000, 8001}, testStruct.Database.Ports)
+	assert.True(t, time.Date(2024, 5, 27, 22, 0, 0, 0, time.UTC).Equal(testStruct.Database.Updated))
+	assert.Equal(t, 2, len(testStruct.Servers))
+	assert.Equal(t, "alpha", testStruct.Servers[0].Name)
+	assert.Equal(t, uint16(10), testStruct.Servers[0].Port)
+	assert.Equal(t, []string{"a", "b"}, testStruct.Servers[0].Tags)
+	assert.Equal(t, 1.0, testStruct.Servers[0].Ratio)
+	assert.Equal(t, "beta", testStruct.Servers[1].Name)
+	assert.Equal(t, uint16(8080), testStruct.Servers[1].Port)
+}
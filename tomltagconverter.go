@@ -0,0 +1,87 @@
+package dynamictags
+
+import (
+	"reflect"
+
+	"github.com/PaesslerAG/jsonpath"
+)
+
+const (
+	TOML_TAG = "toml"
+)
+
+type TomlTagConverter struct {
+	tomlData any
+	// Whole document and root path. Used to apply profile overlay
+	tree     any
+	rootPath string
+}
+
+// Set structure field with 'toml' tag to value from toml document.
+// Path semantic is the same as for 'json' tag. Tables are mapped to nested
+// structures, arrays of tables to slices of structures and date-time values
+// to time.Time fields.
+// Parameters:
+//   - content parsed toml document (see ReadToml)
+//   - rootPath json path to the root of processed structure (like '$.database')
+//
+// Returns:
+//   - Toml tag converter if success.
+//   - error if rootPath is not found
+func NewTomlTagConverter(content any, rootPath string) (TagConverterer, error) {
+	conv := TomlTagConverter{tree: content, rootPath: rootPath}
+	var err error
+	conv.tomlData, err = jsonpath.Get(rootPath, conv.tree)
+	if err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *TomlTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	data, ok := getTreeValue(conv.tomlData, tag, path)
+	return data, ok, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv TomlTagConverter) GetTag() string {
+	return TOML_TAG
+}
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv TomlTagConverter) HasEncryptedValues() bool {
+	return true
+}
+
+// Returns converter for the profile. If document has overlay for the
+// profile (see ApplyProfile) new converter for the overlaid document is
+// returned.
+// Parameters:
+//   - profile profile name
+//
+// Returns:
+//   - converter for the profile
+//   - error if root path is not found in the overlaid document
+func (conv *TomlTagConverter) WithProfile(profile string) (TagConverterer, error) {
+	tree, ok := applyProfile(conv.tree, profile)
+	if !ok {
+		return conv, nil
+	}
+	return NewTomlTagConverter(tree, conv.rootPath)
+}
@@ -0,0 +1,44 @@
+package dynamictags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	TOML_DATA = `
+[root.testcfg1]
+val1 = 123
+val2 = true
+val3 = "testdata"
+`
+	EXPECTED_TOML_TAG = "toml"
+)
+
+func TestTomlTagConverter(t *testing.T) {
+	doc, err := ReadToml([]byte(TOML_DATA))
+	assert.NoError(t, err)
+
+	// Case 1 correct create tag converter
+	conv, err := NewTomlTagConverter(doc, TEST_PATH)
+	assert.NoError(t, err)
+	assert.NotNil(t, conv)
+	assert.Equal(t, EXPECTED_TOML_TAG, conv.GetTag())
+	// Get existed value
+	val, ok, err := conv.GetSimpleValue("val1", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, int64(123), val)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// Get non existed value
+	val, ok, err = conv.GetSimpleValue("val1567", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Nil(t, val)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Case 2 incorrect path
+	conv, err = NewTomlTagConverter(doc, INCORRECT_PATH)
+	assert.Error(t, err)
+	assert.Nil(t, conv)
+}
@@ -0,0 +1,80 @@
+package dynamictags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+)
+
+// Returns absolute json path for the tag.
+// Parameters:
+//   - tag processed tag. If tag starts with '$' it is absolute path
+//   - path json path to structure field (like '$.InternalStructure')
+//
+// Returns:
+//   - absolute json path
+func composeTreePath(tag string, path string) string {
+	if strings.HasPrefix(tag, "$") {
+		return tag
+	}
+	// If path is relative
+	return path + "." + tag
+}
+
+// Returns value from configuration tree.
+// Parameters:
+//   - tree configuration tree (result of json unmarshaling)
+//   - tag processed tag
+//   - path json path to structure field
+//
+// Returns:
+//   - value
+//   - true if value exists
+func getTreeValue(tree any, tag string, path string) (any, bool) {
+	data, err := jsonpath.Get(composeTreePath(tag, path), tree)
+	return data, err == nil
+}
+
+// Convert generic tree to the tree supported by json path. I.e. all maps
+// are converted to map[string]interface{} and all slices to []interface{}.
+// Parameters:
+//   - tree source tree (for example result of yaml unmarshaling)
+//
+// Returns:
+//   - normalized tree
+func normalizeTree(tree any) any {
+	switch val := tree.(type) {
+	case map[string]interface{}:
+		for key, item := range val {
+			val[key] = normalizeTree(item)
+		}
+		return val
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			res[fmt.Sprint(key)] = normalizeTree(item)
+		}
+		return res
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normalizeTree(item)
+		}
+		return val
+	}
+	return tree
+}
+
+// Returns key in flat key-value storage (like ini or properties file) for the tag.
+// Key is the json path without '$.' prefix. I.e. for path '$.database' and
+// tag 'port' key is 'database.port'.
+// Parameters:
+//   - tag processed tag. If tag starts with '$' it is absolute path
+//   - path json path to structure field
+//
+// Returns:
+//   - key
+func composeFlatKey(tag string, path string) string {
+	key := strings.TrimPrefix(composeTreePath(tag, path), "$")
+	return strings.TrimPrefix(key, ".")
+}
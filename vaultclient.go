@@ -0,0 +1,170 @@
+package dynamictags
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	VAULT_DEFAULT_ADDRESS = "https://127.0.0.1:8200"
+	VAULT_ADDRESS_ENV     = "VAULT_ADDR"
+	VAULT_TOKEN_ENV       = "VAULT_TOKEN"
+	VAULT_TOKEN_FILE_ENV  = "VAULT_TOKEN_FILE"
+	VAULT_TOKEN_FILE      = ".vault-token"
+)
+
+// Client of HashiCorp Vault compatible http API.
+type VaultClient struct {
+	address   string
+	token     string
+	tokenFile string
+	client    *http.Client
+}
+
+// Secret read from Vault.
+type VaultSecret struct {
+	// Secret data. For KV v2 secrets engine data of the secret version
+	Data map[string]interface{}
+	// Lease identifier. Empty for static secrets
+	LeaseID string
+	// Lease duration. 0 if secret has no lease
+	LeaseDuration time.Duration
+	// True if lease can be renewed
+	Renewable bool
+}
+
+// Vault http API response.
+type vaultResponse struct {
+	Data          map[string]interface{} `json:"data"`
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int64                  `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+}
+
+// Create Vault client.
+// Parameters:
+//   - address server address (like 'https://127.0.0.1:8200'). If empty
+//     'VAULT_ADDR' environment variable or default address is used.
+//     Token is get from 'VAULT_TOKEN' environment variable. If the variable
+//     is not defined token is read from file specified by 'VAULT_TOKEN_FILE'
+//     environment variable or from '~/.vault-token' file
+//
+// Returns:
+//   - Vault client
+func NewVaultClient(address string) *VaultClient {
+	if address == "" {
+		address = getEnvDefault(VAULT_ADDRESS_ENV, VAULT_DEFAULT_ADDRESS)
+	}
+	tokenFile := getEnvDefault(VAULT_TOKEN_FILE_ENV, "")
+	if tokenFile == "" {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			tokenFile = filepath.Join(home, VAULT_TOKEN_FILE)
+		}
+	}
+	return &VaultClient{
+		address:   strings.TrimSuffix(address, "/"),
+		token:     getEnvDefault(VAULT_TOKEN_ENV, ""),
+		tokenFile: tokenFile,
+		client:    &http.Client{Timeout: HTTP_DEFAULT_TIMEOUT},
+	}
+}
+
+// Set token.
+// Parameters:
+//   - token token
+func (client *VaultClient) SetToken(token string) {
+	client.token = token
+}
+
+// Set token file. File is read before every request, so token can be
+// rotated (for example by Vault agent). Token set by SetToken has priority.
+// Parameters:
+//   - path path to the token file
+func (client *VaultClient) SetTokenFile(path string) {
+	client.tokenFile = path
+}
+
+// Set http client. Client can be used to configure TLS.
+// Parameters:
+//   - httpClient http client
+func (client *VaultClient) SetClient(httpClient *http.Client) {
+	client.client = httpClient
+}
+
+func (client *VaultClient) getToken() (string, error) {
+	if client.token != "" || client.tokenFile == "" {
+		return client.token, nil
+	}
+	token, err := readValueFile(client.tokenFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return strings.TrimSpace(token), err
+}
+
+// Read secret. Data of KV v2 secrets engine (path like 'kv/data/db') is
+// unwrapped, i.e. secret data contains fields of the secret version.
+// Parameters:
+//   - path secret path (like 'kv/data/prod/db')
+//
+// Returns:
+//   - secret or nil if secret doesn't exist
+//   - error in case of error
+func (client *VaultClient) Read(path string) (*VaultSecret, error) {
+	token, err := client.getToken()
+	if err != nil {
+		return nil, err
+	}
+	reqUrl := client.address + "/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	resp, err := client.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HttpStatusError{Url: reqUrl, StatusCode: resp.StatusCode}
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	response := vaultResponse{}
+	err = json.Unmarshal(content, &response)
+	if err != nil {
+		return nil, err
+	}
+	secret := VaultSecret{
+		Data:          response.Data,
+		LeaseID:       response.LeaseID,
+		LeaseDuration: time.Duration(response.LeaseDuration) * time.Second,
+		Renewable:     response.Renewable,
+	}
+	// KV v2 response has data and metadata of the version
+	versionData, isMap := response.Data["data"].(map[string]interface{})
+	_, hasMetadata := response.Data["metadata"]
+	if isMap && hasMetadata {
+		secret.Data = versionData
+	}
+	// Deleted KV v2 version has null data
+	if response.Data["data"] == nil && hasMetadata {
+		return nil, nil
+	}
+	return &secret, nil
+}
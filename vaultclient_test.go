@@ -0,0 +1,129 @@
+package dynamictags
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	VAULT_TEST_TOKEN = "vault-token"
+)
+
+// Fake Vault server. Counts requests of every path.
+type fakeVault struct {
+	mutex     sync.Mutex
+	responses map[string]any
+	requests  map[string]int
+}
+
+func newFakeVault() *fakeVault {
+	return &fakeVault{
+		responses: map[string]any{
+			"kv/data/prod/db": map[string]any{
+				"data": map[string]any{
+					"data":     map[string]any{"user": "admin", "password": "secret", "port": 5432},
+					"metadata": map[string]any{"version": 2},
+				},
+				"lease_duration": 0,
+			},
+			"kv/data/prod/deleted": map[string]any{
+				"data": map[string]any{"data": nil, "metadata": map[string]any{"version": 3}},
+			},
+			"database/creds/app": map[string]any{
+				"data":           map[string]any{"username": "v-app-1", "password": "generated"},
+				"lease_id":       "database/creds/app/123",
+				"lease_duration": 3600,
+				"renewable":      true,
+			},
+		},
+		requests: make(map[string]int),
+	}
+}
+
+func (fake *fakeVault) getRequests(path string) int {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.requests[path]
+}
+
+func (fake *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	if r.Header.Get("X-Vault-Token") != VAULT_TEST_TOKEN {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	fake.requests[path]++
+	response, ok := fake.responses[path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func TestVaultClientRead(t *testing.T) {
+	server := httptest.NewServer(newFakeVault())
+	defer server.Close()
+	client := NewVaultClient(server.URL)
+	client.SetToken(VAULT_TEST_TOKEN)
+	// Case 1 KV v2 secret
+	secret, err := client.Read("kv/data/prod/db")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"user": "admin", "password": "secret", "port": 5432.0}, secret.Data)
+	assert.Equal(t, time.Duration(0), secret.LeaseDuration)
+	// Case 2 secret with lease
+	secret, err = client.Read("/database/creds/app")
+	assert.NoError(t, err)
+	assert.Equal(t, "v-app-1", secret.Data["username"])
+	assert.Equal(t, "database/creds/app/123", secret.LeaseID)
+	assert.Equal(t, time.Hour, secret.LeaseDuration)
+	assert.True(t, secret.Renewable)
+	// Case 3 unknown and deleted secrets
+	secret, err = client.Read("kv/data/prod/unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, secret)
+	secret, err = client.Read("kv/data/prod/deleted")
+	assert.NoError(t, err)
+	assert.Nil(t, secret)
+	// Case 4 incorrect token
+	client.SetToken("incorrect")
+	_, err = client.Read("kv/data/prod/db")
+	assert.Error(t, err)
+}
+
+func TestVaultClientToken(t *testing.T) {
+	server := httptest.NewServer(newFakeVault())
+	defer server.Close()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	err := os.WriteFile(tokenFile, []byte(VAULT_TEST_TOKEN+"\n"), 0o600)
+	assert.NoError(t, err)
+	// Case 1 address and token from environment variables
+	t.Setenv(VAULT_ADDRESS_ENV, server.URL)
+	t.Setenv(VAULT_TOKEN_ENV, VAULT_TEST_TOKEN)
+	client := NewVaultClient("")
+	secret, err := client.Read("kv/data/prod/db")
+	assert.NoError(t, err)
+	assert.NotNil(t, secret)
+	// Case 2 token file from environment variable
+	t.Setenv(VAULT_TOKEN_ENV, "")
+	t.Setenv(VAULT_TOKEN_FILE_ENV, tokenFile)
+	client = NewVaultClient("")
+	secret, err = client.Read("kv/data/prod/db")
+	assert.NoError(t, err)
+	assert.NotNil(t, secret)
+	// Case 3 token file doesn't exist
+	client.SetTokenFile(filepath.Join(t.TempDir(), "unknown"))
+	_, err = client.Read("kv/data/prod/db")
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package dynamictags
+
+// Create processor to process 'secret' tag.
+// This processor replace structure field with 'secret' tag
+// by value of Vault secret field. Example usage:
+//
+//	type Database struct {
+//	  User     string `secret:"kv/data/${ENV}/db#user"`
+//	  Password string `secret:"kv/data/${ENV}/db#password"`
+//	}
+//	processor := NewVaultProcessor(NewVaultClient(""))
+//	processor.Process(&databaseConfiguration, nil)
+//
+// Parameters:
+//   - client Vault client
+//
+// Returns:
+//   - Secret tag processor.
+func NewVaultProcessor(client *VaultClient) *DynamicTagProcessor {
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(NewSecretTagConverter(client))
+	return &processor
+}
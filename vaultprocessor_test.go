@@ -0,0 +1,40 @@
+package dynamictags
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type VaultTestStruct struct {
+	User     string `secret:"kv/data/${VAULT_TEST_ENV}/db#user"`
+	Password string `secret:"kv/data/${VAULT_TEST_ENV}/db#password"`
+	Port     int    `secret:"kv/data/${VAULT_TEST_ENV}/db#port"`
+	Missing  string `secret:"kv/data/${VAULT_TEST_ENV}/unknown#value" default:"default"`
+}
+
+func TestVaultProcessor(t *testing.T) {
+	fake := newFakeVault()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := NewVaultClient(server.URL)
+	client.SetToken(VAULT_TEST_TOKEN)
+	processor := NewVaultProcessor(client)
+	processor.SetDictionaryValue("VAULT_TEST_ENV", "prod")
+	processor.AddTagConverter(NewDefaultTagConverter())
+	// Case 1 secret is read once during Process call
+	data := VaultTestStruct{}
+	err := processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, VaultTestStruct{User: "admin", Password: "secret", Port: 5432, Missing: "default"}, data)
+	assert.Equal(t, 1, fake.getRequests("kv/data/prod/db"))
+	// Case 2 cache is cleared after Process call
+	err = processor.Process(&data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fake.getRequests("kv/data/prod/db"))
+	// Case 3 server error
+	client.SetToken("incorrect")
+	err = processor.Process(&data, nil)
+	assert.Error(t, err)
+}
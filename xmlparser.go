@@ -0,0 +1,243 @@
+package dynamictags
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Node of parsed xml document. Namespaces are ignored, i.e. only local names
+// of elements and attributes are used.
+type XmlNode struct {
+	// Element name. Empty for document node
+	Name string
+	// Element attributes
+	Attributes map[string]string
+	// Child elements
+	Children []*XmlNode
+	// Element text without leading and trailing whitespaces
+	Text string
+}
+
+// Parse xml content.
+// Parameters:
+//   - content xml content
+//
+// Returns:
+//   - document node. Root element is the child of document node
+//   - error in case of syntax error (*SyntaxError)
+func ReadXml(content []byte) (*XmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	doc := &XmlNode{}
+	stack := []*XmlNode{doc}
+	texts := []*strings.Builder{{}}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			line, column := decoder.InputPos()
+			return nil, &SyntaxError{Line: line, Column: column, Msg: err.Error()}
+		}
+		switch elem := token.(type) {
+		case xml.StartElement:
+			node := &XmlNode{
+				Name:       elem.Name.Local,
+				Attributes: make(map[string]string, len(elem.Attr)),
+			}
+			for _, attr := range elem.Attr {
+				node.Attributes[attr.Name.Local] = attr.Value
+			}
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+			stack = append(stack, node)
+			texts = append(texts, &strings.Builder{})
+		case xml.CharData:
+			texts[len(texts)-1].Write(elem)
+		case xml.EndElement:
+			node := stack[len(stack)-1]
+			node.Text = strings.TrimSpace(texts[len(texts)-1].String())
+			stack = stack[:len(stack)-1]
+			texts = texts[:len(texts)-1]
+		}
+	}
+	if len(doc.Children) == 0 {
+		return nil, &SyntaxError{Line: 1, Column: 1, Msg: "no root element"}
+	}
+	return doc, nil
+}
+
+// Select values by path. Path is a subset of XPath:
+//   - 'name' child elements
+//   - '@name' attribute
+//   - 'name[2]' element by index (starts from 1)
+//   - 'name[@attr]', 'name[@attr='value']' elements filtered by attribute
+//
+// Steps are separated by '/' or '.'. Path started with '$' is relative to
+// the node, path started with '/' is relative to the document.
+// Parameters:
+//   - doc document node
+//   - node context node
+//   - path path
+//
+// Returns:
+//   - selected nodes (if last step selects elements)
+//   - selected attribute values (if last step selects attribute)
+//   - error if path is incorrect
+func selectXml(doc *XmlNode, node *XmlNode, path string) ([]*XmlNode, []string, error) {
+	steps, absolute, err := splitXmlPath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes := []*XmlNode{node}
+	if absolute {
+		nodes = []*XmlNode{doc}
+	}
+	for i, step := range steps {
+		if strings.HasPrefix(step, "@") {
+			if i != len(steps)-1 {
+				return nil, nil, errors.New("attribute should be the last step of path '" + path + "'")
+			}
+			values := make([]string, 0, len(nodes))
+			for _, curr := range nodes {
+				val, ok := curr.Attributes[step[1:]]
+				if ok {
+					values = append(values, val)
+				}
+			}
+			return nil, values, nil
+		}
+		nodes, err = selectXmlChildren(nodes, step)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return nodes, nil, nil
+}
+
+// Split path to steps. Separators inside predicates are ignored.
+func splitXmlPath(path string) ([]string, bool, error) {
+	steps := make([]string, 0)
+	absolute := false
+	path = strings.TrimPrefix(path, "$")
+	var quote byte
+	depth := 0
+	start := 0
+	for i := 0; i <= len(path); i++ {
+		if i < len(path) {
+			ch := path[i]
+			if quote != 0 {
+				if ch == quote {
+					quote = 0
+				}
+				continue
+			}
+			switch ch {
+			case '\'', '"':
+				quote = ch
+				continue
+			case '[':
+				depth++
+				continue
+			case ']':
+				depth--
+				continue
+			case '.', '/':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		step := path[start:i]
+		start = i + 1
+		if step != "" {
+			steps = append(steps, step)
+			continue
+		}
+		// '/' at the beginning of a step means absolute path
+		if i < len(path) && path[i] == '/' {
+			steps = steps[:0]
+			absolute = true
+		}
+	}
+	if quote != 0 || depth != 0 {
+		return nil, false, errors.New("incorrect path '" + path + "'")
+	}
+	return steps, absolute, nil
+}
+
+func selectXmlChildren(nodes []*XmlNode, step string) ([]*XmlNode, error) {
+	name := step
+	predicates := make([]string, 0)
+	indx := strings.Index(step, "[")
+	if indx >= 0 {
+		name = step[:indx]
+		rest := step[indx:]
+		for rest != "" {
+			end := strings.Index(rest, "]")
+			if !strings.HasPrefix(rest, "[") || end < 0 {
+				return nil, errors.New("incorrect predicate in '" + step + "'")
+			}
+			predicates = append(predicates, strings.TrimSpace(rest[1:end]))
+			rest = rest[end+1:]
+		}
+	}
+	res := make([]*XmlNode, 0)
+	for _, node := range nodes {
+		children := make([]*XmlNode, 0)
+		for _, child := range node.Children {
+			if name == "*" || child.Name == name {
+				children = append(children, child)
+			}
+		}
+		for _, predicate := range predicates {
+			var err error
+			children, err = filterXmlNodes(children, predicate)
+			if err != nil {
+				return nil, err
+			}
+		}
+		res = append(res, children...)
+	}
+	return res, nil
+}
+
+func filterXmlNodes(nodes []*XmlNode, predicate string) ([]*XmlNode, error) {
+	if !strings.HasPrefix(predicate, "@") {
+		indx, err := strconv.Atoi(predicate)
+		if err != nil {
+			return nil, errors.New("unsupported predicate '" + predicate + "'")
+		}
+		if indx < 1 || indx > len(nodes) {
+			return []*XmlNode{}, nil
+		}
+		return []*XmlNode{nodes[indx-1]}, nil
+	}
+	attr := predicate[1:]
+	value := ""
+	hasValue := false
+	eqIndx := strings.Index(attr, "=")
+	if eqIndx >= 0 {
+		value = strings.TrimSpace(attr[eqIndx+1:])
+		attr = strings.TrimSpace(attr[:eqIndx])
+		if len(value) < 2 || (value[0] != '\'' && value[0] != '"') || value[len(value)-1] != value[0] {
+			return nil, errors.New("unsupported predicate '" + predicate + "'")
+		}
+		value = value[1 : len(value)-1]
+		hasValue = true
+	}
+	res := make([]*XmlNode, 0, len(nodes))
+	for _, node := range nodes {
+		val, ok := node.Attributes[attr]
+		if ok && (!hasValue || val == value) {
+			res = append(res, node)
+		}
+	}
+	return res, nil
+}
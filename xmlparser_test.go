@@ -0,0 +1,75 @@
+package dynamictags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const XML_PARSER_DATA = `<?xml version="1.0"?>
+<!-- comment -->
+<config version="2">
+	<server id="main" enabled="true">
+		<port>8080</port>
+	</server>
+	<server id="backup">
+		<port> 8081 </port>
+	</server>
+	<name>test</name>
+</config>
+`
+
+func selectXmlTexts(t *testing.T, doc *XmlNode, path string) []string {
+	nodes, values, err := selectXml(doc, doc, path)
+	assert.NoError(t, err)
+	for _, node := range nodes {
+		values = append(values, node.Text)
+	}
+	return values
+}
+
+func TestReadXml(t *testing.T) {
+	doc, err := ReadXml([]byte(XML_PARSER_DATA))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(doc.Children))
+	root := doc.Children[0]
+	assert.Equal(t, "config", root.Name)
+	assert.Equal(t, "2", root.Attributes["version"])
+	assert.Equal(t, 3, len(root.Children))
+	assert.Equal(t, "8081", root.Children[1].Children[0].Text)
+}
+
+func TestSelectXml(t *testing.T) {
+	doc, err := ReadXml([]byte(XML_PARSER_DATA))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test"}, selectXmlTexts(t, doc, "/config/name"))
+	assert.Equal(t, []string{"test"}, selectXmlTexts(t, doc, "$.config.name"))
+	assert.Equal(t, []string{"2"}, selectXmlTexts(t, doc, "config/@version"))
+	assert.Equal(t, []string{"8080", "8081"}, selectXmlTexts(t, doc, "config/server/port"))
+	assert.Equal(t, []string{"8081"}, selectXmlTexts(t, doc, "config/server[2]/port"))
+	assert.Equal(t, []string{"8081"}, selectXmlTexts(t, doc, "config/server[@id='backup']/port"))
+	assert.Equal(t, []string{"main"}, selectXmlTexts(t, doc, "config/server[@enabled]/@id"))
+	assert.Equal(t, []string{"8080"}, selectXmlTexts(t, doc, "$.config.server[@id=\"main\"].port"))
+	assert.Equal(t, []string{"test"}, selectXmlTexts(t, doc, "$.config.server./config/name"))
+	assert.Empty(t, selectXmlTexts(t, doc, "config/server[3]"))
+	assert.Empty(t, selectXmlTexts(t, doc, "config/unknown"))
+	// Incorrect paths
+	_, _, err = selectXml(doc, doc, "config/@version/name")
+	assert.Error(t, err)
+	_, _, err = selectXml(doc, doc, "config/server[@id='main'")
+	assert.Error(t, err)
+	_, _, err = selectXml(doc, doc, "config/server[last()]")
+	assert.Error(t, err)
+}
+
+func TestReadXmlErrors(t *testing.T) {
+	// Case 1 not closed element
+	_, err := ReadXml([]byte("<config>\n<name>test</config>"))
+	assert.Error(t, err)
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, syntaxErr.Line)
+	// Case 2 empty document
+	_, err = ReadXml([]byte(""))
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package dynamictags
+
+// Create processor to process 'xml' tag.
+// This processor replace structure field with 'xml' tag
+// by value get from xml. Example usage:
+//
+//	content, err := os.ReadFile("serverconfiguration.xml")
+//	if err != nil {
+//	  return err
+//	}
+//	processor, err := NewXmlProcessor(content, "/config/database")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&databaseConfiguration, nil)
+//
+// Returns:
+//   - Xml tag processor if success.
+//   - error if error occured during processor creation
+func NewXmlProcessor(content []byte, rootPath string) (*DynamicTagProcessor, error) {
+	doc, err := ReadXml(content)
+	if err != nil {
+		return nil, err
+	}
+	converter, err := NewXmlTagConverter(doc, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(converter)
+	return &processor, nil
+}
@@ -0,0 +1,95 @@
+package dynamictags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	EXPECTED_XML_TAG = "xml"
+	XML_PROC_DATA    = `
+<config>
+	<name>global</name>
+	<database host="db.local">
+		<port>5432</port>
+		<replica>r1.local</replica>
+		<replica>r2.local</replica>
+	</database>
+	<server id="main"><port>8080</port></server>
+	<server id="backup"><port>8081</port></server>
+</config>
+`
+)
+
+type XmlServerTestStruct struct {
+	Id   string `xml:"@id"`
+	Port int    `xml:"port"`
+}
+
+type XmlDatabaseTestStruct struct {
+	Host     string   `xml:"@host"`
+	Port     uint16   `xml:"port"`
+	Replicas []string `xml:"replica"`
+	Name     string   `xml:"/config/name"`
+}
+
+type XmlTestStruct struct {
+	Database XmlDatabaseTestStruct `xml:"database"`
+	Backup   XmlServerTestStruct   `xml:"server[@id='${XML_SERVER_ID}']"`
+	Servers  []XmlServerTestStruct `xml:"server"`
+}
+
+func TestXmlTagConverter(t *testing.T) {
+	doc, err := ReadXml([]byte(XML_PROC_DATA))
+	assert.NoError(t, err)
+	// Case 1 correct create tag converter
+	conv, err := NewXmlTagConverter(doc, "/config")
+	assert.NoError(t, err)
+	assert.Equal(t, EXPECTED_XML_TAG, conv.GetTag())
+	val, isSet, err := conv.GetSimpleValue("name", reflect.StructField{}, reflect.ValueOf(""), "$")
+	assert.Equal(t, "global", val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 2 slice value
+	val, isSet, err = conv.GetSimpleValue("replica", reflect.StructField{}, reflect.ValueOf([]string{}), "$.database")
+	assert.Equal(t, []interface{}{"r1.local", "r2.local"}, val)
+	assert.True(t, isSet)
+	assert.NoError(t, err)
+	// Case 3 not existed value
+	_, isSet, err = conv.GetSimpleValue("unknown", reflect.StructField{}, reflect.ValueOf(""), "$")
+	assert.False(t, isSet)
+	assert.NoError(t, err)
+	// Case 4 incorrect root path
+	conv, err = NewXmlTagConverter(doc, "/unknown")
+	assert.Error(t, err)
+	assert.Nil(t, conv)
+	// Case 5 no document
+	conv, err = NewXmlTagConverter(nil, "/config")
+	assert.Error(t, err)
+	assert.Nil(t, conv)
+}
+
+func TestXmlConversion(t *testing.T) {
+	processor, err := NewXmlProcessor([]byte(XML_PROC_DATA), "/config")
+	assert.NoError(t, err)
+	processor.SetDictionaryValue("XML_SERVER_ID", "backup")
+	testStruct := XmlTestStruct{}
+	err = processor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "db.local", testStruct.Database.Host)
+	assert.Equal(t, uint16(5432), testStruct.Database.Port)
+	assert.Equal(t, []string{"r1.local", "r2.local"}, testStruct.Database.Replicas)
+	assert.Equal(t, "global", testStruct.Database.Name)
+	assert.Equal(t, "backup", testStruct.Backup.Id)
+	assert.Equal(t, 8081, testStruct.Backup.Port)
+	// Slice of structures
+	expectedServers := []XmlServerTestStruct{{Id: "main", Port: 8080}, {Id: "backup", Port: 8081}}
+	assert.Equal(t, expectedServers, testStruct.Servers)
+
+	// Syntax error
+	processor, err = NewXmlProcessor([]byte("<config>"), "/config")
+	assert.Error(t, err)
+	assert.Nil(t, processor)
+}
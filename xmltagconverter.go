@@ -0,0 +1,102 @@
+package dynamictags
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+const (
+	XML_TAG = "xml"
+)
+
+type XmlTagConverter struct {
+	doc  *XmlNode
+	root *XmlNode
+}
+
+// Set structure field with 'xml' tag to value from xml document.
+// Tag value is path in XPath subset: child elements ('server/port'), attributes
+// ('@name'), indexes started from 1 ('server[2]') and attribute predicates
+// ('server[@id='main']'). Steps can be separated by '/' or '.'. Paths of nested structures are
+// composed in the same way as json paths. Path started with '$' is relative
+// to the root node, path started with '/' is relative to the document.
+// Parameters:
+//   - content parsed xml document (see ReadXml)
+//   - rootPath path to the root node of processed structure (like '/config/database')
+//
+// Returns:
+//   - Xml tag converter if success.
+//   - error if rootPath is not found
+func NewXmlTagConverter(content *XmlNode, rootPath string) (TagConverterer, error) {
+	if content == nil {
+		return nil, errors.New("xml document is nil")
+	}
+	nodes, _, err := selectXml(content, content, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("xml element '" + rootPath + "' not found")
+	}
+	return &XmlTagConverter{doc: content, root: nodes[0]}, nil
+}
+
+// Returns conversion result. For slice fields all found values are returned.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *XmlTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	nodes, values, err := selectXml(conv.doc, conv.root, composeTreePath(tag, path))
+	if err != nil {
+		return nil, false, err
+	}
+	for _, node := range nodes {
+		values = append(values, node.Text)
+	}
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+	if v.Kind() == reflect.Slice && v.Type() != rawMessageType {
+		res := make([]interface{}, 0, len(values))
+		for _, val := range values {
+			res = append(res, val)
+		}
+		return res, true, nil
+	}
+	return values[0], true, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv XmlTagConverter) GetTag() string {
+	return XML_TAG
+}
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv XmlTagConverter) HasEncryptedValues() bool {
+	return true
+}
+
+// Returns path to the element of slice of structures. XPath indexes start
+// from 1.
+// Parameters:
+//   - path path to the slice
+//   - index 0-based index of the element
+//
+// Returns:
+//   - path to the element
+func (conv XmlTagConverter) ComposeItemPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index+1) + "]"
+}
@@ -0,0 +1,35 @@
+package dynamictags
+
+// Create processor to process 'yaml' tag.
+// This processor replace structure field with 'yaml' tag
+// by value get from the first yaml document. Example usage:
+//
+//	content, err := os.ReadFile("serverconfiguration.yaml")
+//	if err != nil {
+//	  return err
+//	}
+//	processor, err := NewYamlProcessor(content, "$.database")
+//	if err != nil {
+//	  return err
+//	}
+//	processor.Process(&databaseConfiguration, nil)
+//
+// To process other document use ReadYamlDocument or ReadYamlDocumentByKey
+// and NewYamlTagConverter.
+// Returns:
+//   - Yaml tag processor if success.
+//   - error if error occured during processor creation
+func NewYamlProcessor(content []byte, rootPath string) (*DynamicTagProcessor, error) {
+	doc, err := ReadYamlDocument(content, 0)
+	if err != nil {
+		return nil, err
+	}
+	converter, err := NewYamlTagConverter(doc, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	processor := DynamicTagProcessor{}
+	processor.InitProcessor()
+	processor.AddTagConverter(converter)
+	return &processor, nil
+}
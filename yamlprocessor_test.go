@@ -0,0 +1,70 @@
+package dynamictags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type IntYamlTestStruct struct {
+	IntData    int16  `yaml:"IntIntData"`
+	StringData string `yaml:"IntStrData"`
+}
+
+type YamlTestStruct struct {
+	IntData    int8              `yaml:"${JSON_INT_PREFIX}Data"`
+	UIntData   uint16            `yaml:"UIntData"`
+	FloatData  float32           `yaml:"FloatData"`
+	StringData string            `yaml:"StringData"`
+	BoolData   bool              `yaml:"BoolData"`
+	SliceData  []string          `yaml:"$.slice"`
+	IntStruct  IntYamlTestStruct `yaml:"struct"`
+}
+
+const YAML_PROC_DATA = `
+root:
+  testcfg1:
+    IntData: -123
+    UIntData: 567
+    FloatData: 67.8
+    StringData: testdata
+    BoolData: true
+    slice: [one, two, free]
+    struct:
+      IntIntData: 5566
+      IntStrData: intstring
+`
+
+func TestCreateYamlProcessor(t *testing.T) {
+	// Case 1 correct create processor
+	proc, err := NewYamlProcessor([]byte(YAML_PROC_DATA), TEST_PATH)
+	assert.NoError(t, err)
+	assert.NotNil(t, proc)
+
+	// Case 2 empty content
+	proc, err = NewYamlProcessor([]byte(""), TEST_PATH)
+	assert.Error(t, err)
+	assert.Nil(t, proc)
+
+	// Case 3 incorrect path
+	proc, err = NewYamlProcessor([]byte(YAML_PROC_DATA), INCORRECT_PATH)
+	assert.Error(t, err)
+	assert.Nil(t, proc)
+}
+
+func TestYamlConversion(t *testing.T) {
+	yamlProcessor, err := NewYamlProcessor([]byte(YAML_PROC_DATA), TEST_PATH)
+	assert.NoError(t, err)
+	yamlProcessor.SetDictionaryValue(JSON_INT_PREFIX_KEY, JSON_INT_PREFIX_VAL)
+	testStruct := YamlTestStruct{}
+	err = yamlProcessor.Process(&testStruct, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, EXPECTED_JSON_INT, testStruct.IntData)
+	assert.Equal(t, EXPECTED_JSON_UINT, testStruct.UIntData)
+	assert.Equal(t, EXPECTED_JSON_FLOAT, testStruct.FloatData)
+	assert.Equal(t, EXPECTED_JSON_STRING, testStruct.StringData)
+	assert.Equal(t, EXPECTED_JSON_BOOL, testStruct.BoolData)
+	assert.Equal(t, []string{EXPECTED_JSON_SLICE_VAL0, EXPECTED_JSON_SLICE_VAL1, EXPECTED_JSON_SLICE_VAL2}, testStruct.SliceData)
+	assert.Equal(t, EXPECTED_JSON_INT_INT, testStruct.IntStruct.IntData)
+	assert.Equal(t, EXPECTED_JSON_INT_STRING, testStruct.IntStruct.StringData)
+}
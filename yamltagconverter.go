@@ -0,0 +1,160 @@
+package dynamictags
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/PaesslerAG/jsonpath"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	YAML_TAG = "yaml"
+)
+
+type YamlTagConverter struct {
+	yamlData any
+	// Whole document and root path. Used to apply profile overlay
+	tree     any
+	rootPath string
+}
+
+// Set structure field with 'yaml' tag to value from yaml document.
+// Path semantic is the same as for 'json' tag. I.e. tag value is json path
+// relative to the parent structure path or absolute path if tag starts with '$'.
+// Parameters:
+//   - content parsed yaml document (see ReadYamlDocument)
+//   - rootPath json path to the root of processed structure (like '$.database')
+//
+// Returns:
+//   - Yaml tag converter if success.
+//   - error if rootPath is not found
+func NewYamlTagConverter(content any, rootPath string) (TagConverterer, error) {
+	conv := YamlTagConverter{tree: normalizeTree(content), rootPath: rootPath}
+	var err error
+	conv.yamlData, err = jsonpath.Get(rootPath, conv.tree)
+	if err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+// Returns conversion result.
+// Parameters:
+//   - tag tag value. This value already processed. All tokens like ${ENV_VARIABLE}
+//     already replaced by dictionary value or environment variable value
+//   - t structure field
+//   - v value
+//   - path json path to structure field
+//
+// Returns:
+//   - Value which will set to structure field.
+//   - Flag. If true value will be set. Otherwice it will be skiped
+//   - error in case of error
+func (conv *YamlTagConverter) GetSimpleValue(tag string, t reflect.StructField, v reflect.Value, path string) (any, bool, error) {
+	data, ok := getTreeValue(conv.yamlData, tag, path)
+	return data, ok, nil
+}
+
+// Returns converter tag.
+// Returns:
+//   - processed tag
+func (conv YamlTagConverter) GetTag() string {
+	return YAML_TAG
+}
+
+// Values can be encrypted (see EncryptedValuesSource).
+// Returns:
+//   - true
+func (conv YamlTagConverter) HasEncryptedValues() bool {
+	return true
+}
+
+// Returns converter for the profile. If document has overlay for the
+// profile (see ApplyProfile) new converter for the overlaid document is
+// returned.
+// Parameters:
+//   - profile profile name
+//
+// Returns:
+//   - converter for the profile
+//   - error if root path is not found in the overlaid document
+func (conv *YamlTagConverter) WithProfile(profile string) (TagConverterer, error) {
+	tree, ok := applyProfile(conv.tree, profile)
+	if !ok {
+		return conv, nil
+	}
+	return NewYamlTagConverter(tree, conv.rootPath)
+}
+
+// Read all documents from yaml content. Anchors and aliases are resolved.
+// Parameters:
+//   - content yaml content. Can contain several documents separated by '---'
+//
+// Returns:
+//   - list of parsed documents
+//   - error in case of syntax error
+func ReadYamlDocuments(content []byte) ([]any, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	docs := make([]any, 0)
+	for {
+		var doc any
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, normalizeTree(doc))
+	}
+	return docs, nil
+}
+
+// Read document with specified index from yaml content.
+// Parameters:
+//   - content yaml content
+//   - index document index (starts from 0)
+//
+// Returns:
+//   - parsed document
+//   - error in case of syntax error or if document is not found
+func ReadYamlDocument(content []byte, index int) (any, error) {
+	docs, err := ReadYamlDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(docs) {
+		return nil, fmt.Errorf("yaml document %d not found. Documents count: %d", index, len(docs))
+	}
+	return docs[index], nil
+}
+
+// Read first yaml document which has specified top level key.
+// Parameters:
+//   - content yaml content
+//   - key top level key
+//
+// Returns:
+//   - parsed document
+//   - error in case of syntax error or if document is not found
+func ReadYamlDocumentByKey(content []byte, key string) (any, error) {
+	docs, err := ReadYamlDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		docMap, ok := doc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		_, ok = docMap[key]
+		if ok {
+			return doc, nil
+		}
+	}
+	return nil, fmt.Errorf("yaml document with key '%s' not found", key)
+}
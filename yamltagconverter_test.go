@@ -0,0 +1,82 @@
+package dynamictags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	YAML_DATA = `
+defaults: &defaults
+  timeout: 30
+  retries: 3
+root:
+  testcfg1:
+    <<: *defaults
+    val1: 123
+    val2: true
+    val3: testdata
+`
+	YAML_MULTI_DOC_DATA = `
+kind: service
+name: first
+---
+kind: database
+port: 5432
+`
+	EXPECTED_YAML_TAG = "yaml"
+)
+
+func TestYamlTagConverter(t *testing.T) {
+	doc, err := ReadYamlDocument([]byte(YAML_DATA), 0)
+	assert.NoError(t, err)
+
+	// Case 1 correct create tag converter
+	conv, err := NewYamlTagConverter(doc, TEST_PATH)
+	assert.NoError(t, err)
+	assert.NotNil(t, conv)
+	assert.Equal(t, EXPECTED_YAML_TAG, conv.GetTag())
+	// Get existed value
+	val, ok, err := conv.GetSimpleValue("val1", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, 123, val)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// Get value from alias
+	val, ok, err = conv.GetSimpleValue("timeout", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Equal(t, 30, val)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// Get non existed value
+	val, ok, err = conv.GetSimpleValue("val1567", reflect.StructField{}, reflect.Value{}, "$")
+	assert.Nil(t, val)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Case 2 incorrect path
+	conv, err = NewYamlTagConverter(doc, INCORRECT_PATH)
+	assert.Error(t, err)
+	assert.Nil(t, conv)
+}
+
+func TestReadYamlDocuments(t *testing.T) {
+	docs, err := ReadYamlDocuments([]byte(YAML_MULTI_DOC_DATA))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(docs))
+	// Case 1 select by index
+	doc, err := ReadYamlDocument([]byte(YAML_MULTI_DOC_DATA), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "database", doc.(map[string]interface{})["kind"])
+	_, err = ReadYamlDocument([]byte(YAML_MULTI_DOC_DATA), 2)
+	assert.Error(t, err)
+	// Case 2 select by key
+	doc, err = ReadYamlDocumentByKey([]byte(YAML_MULTI_DOC_DATA), "port")
+	assert.NoError(t, err)
+	assert.Equal(t, 5432, doc.(map[string]interface{})["port"])
+	_, err = ReadYamlDocumentByKey([]byte(YAML_MULTI_DOC_DATA), "unknown")
+	assert.Error(t, err)
+	// Case 3 syntax error
+	_, err = ReadYamlDocuments([]byte("key: [1, 2"))
+	assert.Error(t, err)
+}